@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/editor"
+	"github.com/kompotkot/firn/pkg/frontmatter"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+var addCommand = &command{
+	name:    "add",
+	usage:   "-j <journal> [-t <tag>]...",
+	summary: "Add an entry from standard input or $EDITOR\n\nEntry is read from standard input, or written in $EDITOR when input is a terminal.\nTitle is taken from the first line unless -title is given.",
+	setup:   withDatabase(addEntry),
+}
+
+// labelsFlag collects tag labels from repeated flags, each of them could
+// hold comma separated labels
+type labelsFlag []string
+
+func (l *labelsFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *labelsFlag) Set(value string) error {
+	*l = append(*l, strings.Split(value, ",")...)
+	return nil
+}
+
+// addFrontMatter is a header of the template opened in external editor
+type addFrontMatter struct {
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags,flow"`
+}
+
+// stdinPiped reports whether standard input is not a terminal, like a pipe,
+// a file or /dev/null
+func stdinPiped() bool {
+	return !term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// writeInEditor opens template with title and tags in external editor and
+// returns path of the edited file with its header and body, the file is
+// left for caller to remove. Path is empty if template was not changed.
+func writeInEditor(header addFrontMatter) (string, addFrontMatter, string, error) {
+	template, err := frontmatter.Format(header, "")
+	if err != nil {
+		return "", header, "", err
+	}
+
+	f, err := os.CreateTemp("", "firn-*.md")
+	if err != nil {
+		return "", header, "", err
+	}
+	path := f.Name()
+	_, err = f.Write(template)
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return "", header, "", err
+	}
+
+	cmd := editor.Command(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(path)
+		return "", header, "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		os.Remove(path)
+		return "", header, "", err
+	}
+	if bytes.Equal(data, template) {
+		os.Remove(path)
+		return "", header, "", nil
+	}
+
+	var edited addFrontMatter
+	content, err := frontmatter.Parse(data, &edited)
+	if err != nil {
+		return path, header, "", fmt.Errorf("%w, entry is kept in %s", err, path)
+	}
+	return path, edited, content, nil
+}
+
+func addEntry(fs *flag.FlagSet) cliRunner {
+	var journalRef string
+	fs.StringVar(&journalRef, "journal", "", "journal ID or name to add entry to")
+	fs.StringVar(&journalRef, "j", "", "shorthand for -journal")
+	var labels labelsFlag
+	fs.Var(&labels, "tag", "tag of the entry, could be repeated or comma separated, missing tags are created")
+	fs.Var(&labels, "t", "shorthand for -tag")
+	title := fs.String("title", "", "entry title instead of the first line")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 0 || journalRef == "" {
+			return errUsage
+		}
+
+		journal, err := resolveJournal(ctx, database, journalRef)
+		if err != nil {
+			return err
+		}
+
+		var text, path string
+		header := addFrontMatter{Title: *title, Tags: db.NormalizeTagLabels(labels)}
+		if stdinPiped() {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read standard input: %w", err)
+			}
+			text = string(data)
+		} else {
+			path, header, text, err = writeInEditor(header)
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				return errors.New("entry is not changed, nothing is added")
+			}
+		}
+
+		entryTitle, content := strings.TrimSpace(header.Title), strings.Trim(text, "\n")
+		if entryTitle == "" {
+			entryTitle, content = kb.SplitTitle(text)
+		}
+		if entryTitle == "" {
+			if path != "" {
+				os.Remove(path)
+			}
+			return errors.New("entry is empty, nothing is added")
+		}
+
+		entry, err := database.CreateEntry(ctx, journal.Id, entryTitle, content)
+		if err != nil {
+			// Entry written in editor is kept, so it is not lost
+			if path != "" {
+				return fmt.Errorf("%w, entry is kept in %s", err, path)
+			}
+			return err
+		}
+		if path != "" {
+			os.Remove(path)
+		}
+
+		if len(header.Tags) > 0 {
+			if err := db.SyncEntryTags(ctx, database, journal.Id, entry.Id, header.Tags); err != nil {
+				return fmt.Errorf("entry %s is added, but tags are not assigned: %w", entry.Id, err)
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Added entry %q to journal %q\n", entry.Title, journal.Name)
+		fmt.Fprintln(os.Stdout, entry.Id)
+		return nil
+	}
+}
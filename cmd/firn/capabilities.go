@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+// Kinds of capabilities
+const (
+	capabilityDatabase = "database"
+	capabilityFrontend = "frontend"
+)
+
+// capability is a database backend or a frontend firn could be built with
+type capability struct {
+	kind string
+	name string
+	tag  string // Build tag compiling it in, empty if it is always available
+}
+
+// capabilities lists all backends and frontends known to firn
+var capabilities = []capability{
+	{kind: capabilityDatabase, name: "sqlite", tag: "sqlite"},
+	{kind: capabilityDatabase, name: "psql", tag: "psql"},
+	{kind: capabilityFrontend, name: "server"},
+	{kind: capabilityFrontend, name: "tui", tag: "tui"},
+}
+
+// compiled reports whether capability is registered in this binary by its
+// build tagged imports
+func (c capability) compiled() bool {
+	switch {
+	case c.tag == "":
+		return true
+	case c.kind == capabilityDatabase:
+		return slices.Contains(db.GetAvailableDatabaseTypes(), c.name)
+	case c.kind == capabilityFrontend && c.name == "tui":
+		return moduleTui != nil
+	}
+	return false
+}
+
+// capabilityNames returns names of capabilities of kind, only compiled
+// ones if compiledOnly is set
+func capabilityNames(kind string, compiledOnly bool) []string {
+	var names []string
+	for _, c := range capabilities {
+		if c.kind == kind && (!compiledOnly || c.compiled()) {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+// rebuildHint returns build command adding tag to tags of compiled
+// capabilities
+func rebuildHint(tag string) string {
+	tags := []string{}
+	for _, c := range capabilities {
+		if c.tag != "" && c.compiled() && !slices.Contains(tags, c.tag) {
+			tags = append(tags, c.tag)
+		}
+	}
+	if !slices.Contains(tags, tag) {
+		tags = append(tags, tag)
+	}
+	return fmt.Sprintf("go build -tags %s -o firn ./cmd/firn", strings.Join(tags, ","))
+}
+
+// requireCapability returns error explaining how to get capability of kind
+// if it is unknown or not compiled into this binary
+func requireCapability(kind, name string) error {
+	for _, c := range capabilities {
+		if c.kind != kind || c.name != name {
+			continue
+		}
+		if c.compiled() {
+			return nil
+		}
+
+		available := "none"
+		if names := capabilityNames(kind, true); len(names) > 0 {
+			available = strings.Join(names, ", ")
+		}
+		return fmt.Errorf("%s %q is not compiled into this binary (available: %s), rebuild it with: %s", kind, name, available, rebuildHint(c.tag))
+	}
+
+	return fmt.Errorf("unknown %s %q, must be one of %s", kind, name, strings.Join(capabilityNames(kind, false), ", "))
+}
+
+// printBuildInfo prints version, build settings and capabilities of the
+// binary
+func printBuildInfo(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Version:\t%s\n", FIRN_VERSION)
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(tw, "Module:\t%s %s\n", info.Main.Path, info.Main.Version)
+		fmt.Fprintf(tw, "Go:\t%s\n", info.GoVersion)
+
+		settings := make(map[string]string, len(info.Settings))
+		for _, s := range info.Settings {
+			settings[s.Key] = s.Value
+		}
+		fmt.Fprintf(tw, "Platform:\t%s/%s\n", settings["GOOS"], settings["GOARCH"])
+		fmt.Fprintf(tw, "Build tags:\t%s\n", settings["-tags"])
+		if revision := settings["vcs.revision"]; revision != "" {
+			if settings["vcs.modified"] == "true" {
+				revision += " (modified)"
+			}
+			fmt.Fprintf(tw, "Revision:\t%s\n", revision)
+			fmt.Fprintf(tw, "Revision time:\t%s\n", settings["vcs.time"])
+		}
+	}
+
+	for _, kind := range []string{capabilityDatabase, capabilityFrontend} {
+		fmt.Fprintf(tw, "\n%s:\n", strings.ToUpper(kind[:1])+kind[1:]+"s")
+		for _, c := range capabilities {
+			if c.kind != kind {
+				continue
+			}
+			status := "available"
+			if !c.compiled() {
+				status = fmt.Sprintf("not compiled, build tag %q", c.tag)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", c.name, status)
+		}
+	}
+
+	tw.Flush()
+}
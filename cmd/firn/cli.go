@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kompotkot/firn/internal/types"
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// errUsage is returned by commands called with wrong arguments, help of
+// the command is printed for it
+var errUsage = errors.New("invalid usage")
+
+// cliRunner runs a command against database with positional arguments
+// left after flags
+type cliRunner func(ctx context.Context, database db.Database, args []string) error
+
+// withDatabase turns setup of a command working with database into setup
+// of an action, database is opened only when the command is run
+func withDatabase(setup func(fs *flag.FlagSet) cliRunner) func(fs *flag.FlagSet) action {
+	return func(fs *flag.FlagSet) action {
+		run := setup(fs)
+		return func(ctx context.Context, opts globalOptions, args []string) error {
+			cfg, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			database, err := openDatabase(ctx, cfg)
+			if err != nil {
+				return err
+			}
+			defer database.Close()
+
+			return run(ctx, database, args)
+		}
+	}
+}
+
+// openDatabase connects to configured database without logging to
+// stdout, which is reserved for command output
+func openDatabase(ctx context.Context, cfg *types.Config) (db.Database, error) {
+	database, err := db.CreateDatabase(
+		cfg.Database.Type,
+		cfg.Database.URI,
+		cfg.Database.MaxConns,
+		int64(cfg.Database.ConnMaxLifetime),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database connection: %w", err)
+	}
+
+	if err := database.TestConnection(ctx); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to test database connection: %w", err)
+	}
+
+	return database, nil
+}
+
+// outputFormat is a way command results are printed
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputPlain outputFormat = "plain"
+)
+
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+func (f *outputFormat) Set(value string) error {
+	switch outputFormat(value) {
+	case outputTable, outputJSON, outputPlain:
+		*f = outputFormat(value)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or plain", value)
+}
+
+// outputFlag registers -o/--output flag with table format by default
+func outputFlag(fs *flag.FlagSet) *outputFormat {
+	format := outputTable
+	fs.Var(&format, "output", "output format: table, json or plain")
+	fs.Var(&format, "o", "shorthand for -output")
+	return &format
+}
+
+// printRecords prints rows as aligned table with optional header, as tab separated
+// values without header, or value as JSON
+func printRecords(w io.Writer, format outputFormat, header []string, rows [][]string, value any) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case outputPlain:
+		for _, row := range rows {
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatTime formats timestamp for table or plain output
+func formatTime(format outputFormat, t time.Time) string {
+	if format == outputPlain {
+		return t.Format(time.RFC3339)
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// oneLine replaces line breaks and tabs so value fits into a table cell
+func oneLine(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// sortFlags registers flags of list sort
+func sortFlags(fs *flag.FlagSet) (*string, *bool) {
+	orderBy := fs.String("sort", string(db.OrderByUpdated), "sort by field: updated, created or name")
+	desc := fs.Bool("desc", false, "sort in descending order")
+	return orderBy, desc
+}
+
+func parseOrderBy(value string) (db.OrderBy, error) {
+	orderBy := db.OrderBy(value)
+	if !orderBy.Valid() {
+		return "", fmt.Errorf("unknown sort field %q, expected updated, created or name", value)
+	}
+	return orderBy, nil
+}
+
+// listAllJournals pages through journals until limit is reached, zero
+// limit lists all of them
+func listAllJournals(ctx context.Context, database db.Database, orderBy db.OrderBy, desc bool, limit int) ([]kb.Journal, error) {
+	var journals []kb.Journal
+	for offset := 0; ; offset += db.JOURNAL_LIST_DEFAULT_LIMIT {
+		page, err := database.ListJournals(ctx, orderBy, desc, db.JOURNAL_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return nil, err
+		}
+		journals = append(journals, page...)
+		if limit > 0 && len(journals) >= limit {
+			return journals[:limit], nil
+		}
+		if len(page) < db.JOURNAL_LIST_DEFAULT_LIMIT {
+			return journals, nil
+		}
+	}
+}
+
+// listAllEntries pages through entries of a journal until limit is
+// reached, zero limit lists all of them
+func listAllEntries(ctx context.Context, database db.Database, journalId string, orderBy db.OrderBy, desc bool, limit int) ([]kb.Entry, error) {
+	var entries []kb.Entry
+	for offset := 0; ; offset += db.ENTRY_LIST_DEFAULT_LIMIT {
+		page, err := database.ListEntries(ctx, journalId, orderBy, desc, db.ENTRY_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if limit > 0 && len(entries) >= limit {
+			return entries[:limit], nil
+		}
+		if len(page) < db.ENTRY_LIST_DEFAULT_LIMIT {
+			return entries, nil
+		}
+	}
+}
+
+// resolveJournal finds journal by its ID or by exact name
+func resolveJournal(ctx context.Context, database db.Database, ref string) (*kb.Journal, error) {
+	journal, err := database.GetJournalById(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if journal != nil {
+		return journal, nil
+	}
+
+	journals, err := listAllJournals(ctx, database, db.OrderByName, false, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *kb.Journal
+	for i := range journals {
+		if journals[i].Name != ref {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several journals are named %q, use journal ID", ref)
+		}
+		found = &journals[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", db.ErrJournalNotFound, ref)
+	}
+	return found, nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// Time of all records of fakeDB, tables show it in local time
+var (
+	cliTime      = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	cliTableTime = cliTime.Local().Format("2006-01-02 15:04")
+)
+
+// fakeDB keeps journals, entries and tags in memory in insertion order,
+// new records get sequential IDs
+type fakeDB struct {
+	db.Database
+	journals []kb.Journal
+	entries  []kb.Entry
+	tags     []kb.Tag
+	assigned map[string][]string // Tag IDs by entry ID
+	created  int
+}
+
+// newFakeDB returns database with journal j1 "Personal" holding entries e1
+// and e2, e1 is tagged with work, and empty journal j2 "Work"
+func newFakeDB() *fakeDB {
+	return &fakeDB{
+		journals: []kb.Journal{
+			{Id: "j1", Name: "Personal", CreatedAt: cliTime, UpdatedAt: cliTime},
+			{Id: "j2", Name: "Work", CreatedAt: cliTime, UpdatedAt: cliTime},
+		},
+		entries: []kb.Entry{
+			{Id: "e1", JournalId: "j1", Title: "First", Content: "Text of first\n", CreatedAt: cliTime, UpdatedAt: cliTime},
+			{Id: "e2", JournalId: "j1", Title: "Second\tline", CreatedAt: cliTime, UpdatedAt: cliTime},
+		},
+		tags:     []kb.Tag{{Id: "t1", Label: "work"}, {Id: "t2", Label: "idea"}},
+		assigned: map[string][]string{"e1": {"t1"}},
+	}
+}
+
+func (d *fakeDB) newId(prefix string) string {
+	d.created++
+	return fmt.Sprintf("%s-new%d", prefix, d.created)
+}
+
+func (d *fakeDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+	return page(d.journals, limit, offset), nil
+}
+
+func (d *fakeDB) GetJournalById(ctx context.Context, id string) (*kb.Journal, error) {
+	for _, j := range d.journals {
+		if j.Id == id {
+			return &j, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDB) CreateJournal(ctx context.Context, name string) (*kb.Journal, error) {
+	journal := kb.Journal{Id: d.newId("j"), Name: name, CreatedAt: cliTime, UpdatedAt: cliTime}
+	d.journals = append(d.journals, journal)
+	return &journal, nil
+}
+
+func (d *fakeDB) RenameJournal(ctx context.Context, id, name string) (*kb.Journal, error) {
+	for i := range d.journals {
+		if d.journals[i].Id == id {
+			d.journals[i].Name = name
+			journal := d.journals[i]
+			return &journal, nil
+		}
+	}
+	return nil, db.ErrJournalNotFound
+}
+
+func (d *fakeDB) DeleteJournal(ctx context.Context, id string) error {
+	d.journals = slices.DeleteFunc(d.journals, func(j kb.Journal) bool { return j.Id == id })
+	d.entries = slices.DeleteFunc(d.entries, func(e kb.Entry) bool { return e.JournalId == id })
+	return nil
+}
+
+func (d *fakeDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	var entries []kb.Entry
+	for _, e := range d.entries {
+		if e.JournalId == journalId {
+			entries = append(entries, e)
+		}
+	}
+	return page(entries, limit, offset), nil
+}
+
+func (d *fakeDB) GetEntryById(ctx context.Context, journalId, entryId string) (*kb.Entry, error) {
+	for _, e := range d.entries {
+		if e.JournalId == journalId && e.Id == entryId {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDB) DeleteEntry(ctx context.Context, journalId, entryId string) error {
+	entry, _ := d.GetEntryById(ctx, journalId, entryId)
+	if entry == nil {
+		return db.ErrEntryNotFound
+	}
+	d.entries = slices.DeleteFunc(d.entries, func(e kb.Entry) bool { return e.Id == entryId })
+	delete(d.assigned, entryId)
+	return nil
+}
+
+func (d *fakeDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for _, t := range d.tags {
+		if slices.Contains(d.assigned[entryId], t.Id) {
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+func (d *fakeDB) AssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
+	for _, id := range tagIds {
+		if !slices.Contains(d.assigned[entryId], id) {
+			d.assigned[entryId] = append(d.assigned[entryId], id)
+		}
+	}
+	return nil
+}
+
+func (d *fakeDB) ListTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for _, t := range d.tags {
+		if labels == nil || slices.Contains(labels, t.Label) {
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
+
+func (d *fakeDB) CreateTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for _, label := range labels {
+		i := slices.IndexFunc(d.tags, func(t kb.Tag) bool { return t.Label == label })
+		if i < 0 {
+			d.tags = append(d.tags, kb.Tag{Id: d.newId("t"), Label: label})
+			i = len(d.tags) - 1
+		}
+		tags = append(tags, d.tags[i])
+	}
+	return tags, nil
+}
+
+func (d *fakeDB) DeleteTags(ctx context.Context, ids []string) error {
+	d.tags = slices.DeleteFunc(d.tags, func(t kb.Tag) bool { return slices.Contains(ids, t.Id) })
+	for entryId, tagIds := range d.assigned {
+		d.assigned[entryId] = slices.DeleteFunc(tagIds, func(id string) bool { return slices.Contains(ids, id) })
+	}
+	return nil
+}
+
+// page returns records in [offset, offset+limit)
+func page[T any](records []T, limit, offset int) []T {
+	if offset >= len(records) {
+		return nil
+	}
+	return records[offset:min(offset+limit, len(records))]
+}
+
+// runCli runs command set up by setup with flags and arguments of args
+// against database, returning what it printed to stdout and stderr
+func runCli(t *testing.T, setup func(fs *flag.FlagSet) cliRunner, database db.Database, args []string) (string, string, error) {
+	t.Helper()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	run := setup(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	runErr := run(context.Background(), database, positional)
+	os.Stdout, os.Stderr = origStdout, origStderr
+
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOut, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), string(errOut), runErr
+}
+
+// checkRunErr fails test unless err matches want, errUsage is matched
+// by identity and other errors by text
+func checkRunErr(t *testing.T, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("run error = %v", err)
+	case want == errUsage.Error() && !errors.Is(err, errUsage):
+		t.Errorf("run error = %v, want %v", err, errUsage)
+	case want != "" && (err == nil || err.Error() != want):
+		t.Errorf("run error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/kompotkot/firn/internal/config"
+	"github.com/kompotkot/firn/internal/types"
+)
+
+// command is a node of firn command tree, it either runs an action or
+// groups subcommands
+type command struct {
+	name    string
+	usage   string // Arguments shown after the command name
+	summary string
+
+	commands []*command
+
+	// setup registers flags of the command and returns its action
+	setup func(fs *flag.FlagSet) action
+}
+
+// action runs command with positional arguments left after flags
+type action func(ctx context.Context, opts globalOptions, args []string) error
+
+// globalOptions are flags accepted by every command, set ones override
+// configuration from environment and config file
+type globalOptions struct {
+	config   string
+	dbType   string
+	dbURI    string
+	logLevel string
+}
+
+// register adds global flags to fs keeping values already set
+func (o *globalOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.config, "config", o.config, "config file, overrides FIRN_CONFIG and default $XDG_CONFIG_HOME/firn/config.toml")
+	fs.StringVar(&o.dbType, "db-type", o.dbType, "database type, overrides DATABASE_TYPE")
+	fs.StringVar(&o.dbURI, "db-uri", o.dbURI, "database URI, overrides DATABASE_URI")
+	fs.StringVar(&o.logLevel, "log-level", o.logLevel, "log level: debug, info, warn or error, overrides LOG_LEVEL")
+}
+
+// loadConfig loads configuration from config file and environment
+// overridden by flags
+func (o globalOptions) loadConfig() (*types.Config, error) {
+	cfg, err := config.Load(config.Overrides{
+		ConfigFile:   o.config,
+		LogLevel:     o.logLevel,
+		DatabaseType: o.dbType,
+		DatabaseURI:  o.dbURI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Fail before connecting if database backend is not built in
+	if err := requireCapability(capabilityDatabase, cfg.Database.Type); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// subcommand returns subcommand by its name
+func (c *command) subcommand(name string) *command {
+	for _, sub := range c.commands {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// flagSet returns flags of the command together with global flags, set
+// global ones are stored in opts
+func (c *command) flagSet(path string, opts *globalOptions) (*flag.FlagSet, action) {
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var run action
+	if c.setup != nil {
+		run = c.setup(fs)
+	}
+	opts.register(fs)
+
+	return fs, run
+}
+
+// runCommands runs command from command line arguments and returns exit code
+func runCommands(root *command, args []string) int {
+	var opts globalOptions
+
+	// Global flags could be given before the command name
+	rootFs := flag.NewFlagSet(root.name, flag.ContinueOnError)
+	rootFs.SetOutput(io.Discard)
+	opts.register(rootFs)
+	if err := rootFs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printHelp(os.Stdout, root, nil)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printHelp(os.Stderr, root, nil)
+		return 2
+	}
+	args = rootFs.Args()
+
+	// Walk down the tree by command names
+	current, path := root, []*command{}
+	for len(current.commands) > 0 {
+		if len(args) == 0 {
+			printHelp(os.Stderr, root, path)
+			return 2
+		}
+		if args[0] == "-h" || args[0] == "--help" || args[0] == "-help" {
+			printHelp(os.Stdout, root, path)
+			return 0
+		}
+		sub := current.subcommand(args[0])
+		if sub == nil {
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\nRun '%s -h' for usage.\n", commandPath(root, path)+" "+args[0], commandPath(root, path))
+			return 2
+		}
+		current, path, args = sub, append(path, sub), args[1:]
+	}
+
+	fs, run := current.flagSet(commandPath(root, path), &opts)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printHelp(os.Stdout, root, path)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		printHelp(os.Stderr, root, path)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, opts, positional); err != nil {
+		if errors.Is(err, errUsage) {
+			printHelp(os.Stderr, root, path)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// commandPath returns full name of command such as "firn entry add"
+func commandPath(root *command, path []*command) string {
+	names := []string{root.name}
+	for _, c := range path {
+		names = append(names, c.name)
+	}
+	return strings.Join(names, " ")
+}
+
+// findCommand resolves names of commands to a path in the tree
+func findCommand(root *command, names []string) ([]*command, error) {
+	current, path := root, []*command{}
+	for _, name := range names {
+		sub := current.subcommand(name)
+		if sub == nil {
+			return nil, fmt.Errorf("unknown command: %s", strings.TrimSpace(commandPath(root, path)+" "+name))
+		}
+		current, path = sub, append(path, sub)
+	}
+	return path, nil
+}
+
+// printHelp prints usage of the command at path with its subcommands or
+// flags, global flags are listed last
+func printHelp(w io.Writer, root *command, path []*command) {
+	current := root
+	if len(path) > 0 {
+		current = path[len(path)-1]
+	}
+	name := commandPath(root, path)
+
+	if current.summary != "" {
+		fmt.Fprintf(w, "%s\n\n", current.summary)
+	}
+
+	if len(current.commands) > 0 {
+		fmt.Fprintf(w, "Usage:\n  %s <command> [flags] [args]\n\nCommands:\n", name)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range current.commands {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.name, firstLine(sub.summary))
+		}
+		tw.Flush()
+	} else {
+		fmt.Fprintf(w, "Usage:\n  %s\n", strings.TrimSpace(name+" [flags] "+current.usage))
+		if current.setup != nil {
+			fs := flag.NewFlagSet(name, flag.ContinueOnError)
+			current.setup(fs)
+			if hasFlags(fs) {
+				fmt.Fprintln(w, "\nFlags:")
+				fs.SetOutput(w)
+				fs.PrintDefaults()
+			}
+		}
+	}
+
+	var opts globalOptions
+	global := flag.NewFlagSet(name, flag.ContinueOnError)
+	opts.register(global)
+	fmt.Fprintln(w, "\nGlobal flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+
+	if len(current.commands) > 0 {
+		fmt.Fprintf(w, "\nRun '%s <command> -h' for more information on a command.\n", name)
+	}
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// parseFlags parses flags placed anywhere among positional arguments,
+// everything after "--" is positional
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		// Parse stops at the first positional argument or drops "--" and stops
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
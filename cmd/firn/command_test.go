@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		title      string
+		yes        bool
+		err        string
+	}{
+		{name: "no arguments"},
+		{name: "positional only", args: []string{"a", "b"}, positional: []string{"a", "b"}},
+		{name: "flags first", args: []string{"-title", "T", "-y", "a"}, positional: []string{"a"}, title: "T", yes: true},
+		{name: "flags last", args: []string{"a", "b", "--title=T", "-y"}, positional: []string{"a", "b"}, title: "T", yes: true},
+		{name: "flags between", args: []string{"a", "-y", "b", "-title", "T", "c"}, positional: []string{"a", "b", "c"}, title: "T", yes: true},
+		{name: "double dash", args: []string{"a", "-y", "--", "-title", "b"}, positional: []string{"a", "-title", "b"}, yes: true},
+		{name: "double dash first", args: []string{"--", "-y"}, positional: []string{"-y"}},
+		{name: "dash and empty", args: []string{"-", "", "-y"}, positional: []string{"-", ""}, yes: true},
+		{name: "last flag wins", args: []string{"-title", "A", "x", "-title", "B"}, positional: []string{"x"}, title: "B"},
+		{name: "unknown flag", args: []string{"a", "-force"}, err: "flag provided but not defined: -force"},
+		{name: "missing value", args: []string{"a", "-title"}, err: "flag needs an argument: -title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			title := fs.String("title", "", "")
+			yes := fs.Bool("y", false, "")
+
+			positional, err := parseFlags(fs, tt.args)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseFlags() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(positional, tt.positional) {
+				t.Errorf("parseFlags() = %q, want %q", positional, tt.positional)
+			}
+			if *title != tt.title || *yes != tt.yes {
+				t.Errorf("flags = title %q, y %v, want %q, %v", *title, *yes, tt.title, tt.yes)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/dump"
+)
+
+// Hidden command printing completion candidates, it is called by scripts
+// of all shells so they stay in sync with the command tree
+const completeCommandName = "__complete"
+
+const bashCompletion = `# bash completion for firn, load with: source <(firn completion bash)
+_firn() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(firn __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _firn firn
+`
+
+const zshCompletion = `#compdef firn
+# zsh completion for firn, load with: source <(firn completion zsh)
+_firn() {
+    local -a candidates
+    candidates=(${(f)"$(firn __complete ${words[2,CURRENT-1]} 2>/dev/null)"})
+    if (( ${#candidates} )); then
+        compadd -- $candidates
+    else
+        _files
+    fi
+}
+
+if [ "$funcstack[1]" = "_firn" ]; then
+    _firn "$@"
+else
+    compdef _firn firn
+fi
+`
+
+const fishCompletion = `# fish completion for firn, load with: firn completion fish | source
+function __firn_complete
+    set -l words (commandline -opc)
+    firn __complete $words[2..-1] 2>/dev/null
+end
+complete -c firn -f -a '(__firn_complete)'
+`
+
+// flagValues are known values of flags offered in completion
+var flagValues = map[string]func() []string{
+	"db-type":   db.GetAvailableDatabaseTypes,
+	"log-level": func() []string { return []string{"debug", "info", "warn", "error"} },
+	"output":    func() []string { return []string{"table", "json", "plain"} },
+	"o":         func() []string { return []string{"table", "json", "plain"} },
+	"format":    func() []string { return []string{string(dump.FormatJSON), string(dump.FormatJSONL)} },
+	"on-conflict": func() []string {
+		values := make([]string, 0, len(db.ConflictPolicies))
+		for _, p := range db.ConflictPolicies {
+			values = append(values, string(p))
+		}
+		return values
+	},
+	"sort": func() []string {
+		values := make([]string, 0, len(db.OrderByFields))
+		for _, f := range db.OrderByFields {
+			values = append(values, string(f))
+		}
+		return values
+	},
+}
+
+func completionCommand(fs *flag.FlagSet) action {
+	return func(ctx context.Context, opts globalOptions, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+
+		switch args[0] {
+		case "bash":
+			fmt.Print(bashCompletion)
+		case "zsh":
+			fmt.Print(zshCompletion)
+		case "fish":
+			fmt.Print(fishCompletion)
+		default:
+			return fmt.Errorf("unsupported shell %q, expected bash, zsh or fish", args[0])
+		}
+		return nil
+	}
+}
+
+// printCompletions prints candidates for the word following words, one
+// per line
+func printCompletions(root *command, words []string) {
+	for _, candidate := range completeWords(root, words) {
+		fmt.Fprintln(os.Stdout, candidate)
+	}
+}
+
+// completeWords returns candidates for the word following words typed
+// after "firn": subcommands and flags of the command they lead to, or
+// values of the flag typed last
+func completeWords(root *command, words []string) []string {
+	current := root
+	var opts globalOptions
+	fs, _ := current.flagSet(root.name, &opts)
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "--" {
+			return nil
+		}
+
+		if strings.HasPrefix(word, "-") {
+			name := strings.TrimLeft(word, "-")
+			if strings.Contains(name, "=") {
+				continue
+			}
+			f := fs.Lookup(name)
+			if f == nil || isBoolFlag(f) {
+				continue
+			}
+			// Flag value is the word being completed
+			if i == len(words)-1 {
+				if values, ok := flagValues[name]; ok {
+					return values()
+				}
+				return nil
+			}
+			i++
+			continue
+		}
+
+		if sub := current.subcommand(word); sub != nil && len(current.commands) > 0 {
+			current = sub
+			fs, _ = current.flagSet(sub.name, &opts)
+		}
+	}
+
+	var candidates []string
+	for _, sub := range current.commands {
+		candidates = append(candidates, sub.name)
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 {
+			candidates = append(candidates, "-"+f.Name)
+		} else {
+			candidates = append(candidates, "--"+f.Name)
+		}
+	})
+	return candidates
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/internal/config"
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+var doctorCommand = &command{
+	name:    "doctor",
+	summary: "Check configuration, database connection, schema and data consistency",
+	setup:   doctorSetup,
+}
+
+func doctorSetup(fs *flag.FlagSet) action {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, opts globalOptions, args []string) error {
+		if len(args) != 0 {
+			return errUsage
+		}
+
+		return printDiagnostics(os.Stdout, os.Stderr, *format, runDiagnostics(ctx, opts))
+	}
+}
+
+// printDiagnostics prints results to w and their summary to summary, it
+// fails if any check failed
+func printDiagnostics(w, summary io.Writer, format outputFormat, results []db.CheckResult) error {
+	rows := make([][]string, len(results))
+	var passed, warned, failed int
+	for i, r := range results {
+		rows[i] = []string{strings.ToUpper(string(r.Status)), r.Name, r.Detail}
+		switch r.Status {
+		case db.CheckPass:
+			passed++
+		case db.CheckWarn:
+			warned++
+		case db.CheckFail:
+			failed++
+		}
+	}
+	if err := printRecords(w, format, []string{"STATUS", "CHECK", "DETAIL"}, rows, results); err != nil {
+		return err
+	}
+	fmt.Fprintf(summary, "%d passed, %d warnings, %d failed\n", passed, warned, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// runDiagnostics checks configuration, backend and connection, then runs
+// checks of database if it supports them. Checks depending on a failed one
+// are skipped.
+func runDiagnostics(ctx context.Context, opts globalOptions) []db.CheckResult {
+	var results []db.CheckResult
+
+	cfg, err := config.Load(config.Overrides{
+		ConfigFile:   opts.config,
+		LogLevel:     opts.logLevel,
+		DatabaseType: opts.dbType,
+		DatabaseURI:  opts.dbURI,
+	})
+	if err != nil {
+		return append(results, db.CheckResult{Name: "Config", Status: db.CheckFail, Detail: err.Error()})
+	}
+	source := "defaults and environment"
+	if path := config.UsedFile(opts.config); path != "" {
+		source = path
+	}
+	results = append(results, db.CheckResult{Name: "Config", Status: db.CheckPass, Detail: "loaded from " + source})
+
+	if err := requireCapability(capabilityDatabase, cfg.Database.Type); err != nil {
+		return append(results, db.CheckResult{Name: "Backend", Status: db.CheckFail, Detail: err.Error()})
+	}
+	results = append(results, db.CheckResult{Name: "Backend", Status: db.CheckPass, Detail: cfg.Database.Type})
+
+	// URI is not printed as it could contain password
+	database, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return append(results, db.CheckResult{Name: "Connection", Status: db.CheckFail, Detail: err.Error()})
+	}
+	defer database.Close()
+	results = append(results, db.CheckResult{Name: "Connection", Status: db.CheckPass, Detail: "connected"})
+
+	return append(results, diagnoseDatabase(ctx, cfg.Database.Type, database)...)
+}
+
+// diagnoseDatabase runs checks of database, backends without them are
+// reported with a warning
+func diagnoseDatabase(ctx context.Context, dbType string, database db.Database) []db.CheckResult {
+	diagnoser, ok := database.(db.Diagnoser)
+	if !ok {
+		return []db.CheckResult{{Name: "Schema", Status: db.CheckWarn, Detail: fmt.Sprintf("%s backend does not support schema checks", dbType)}}
+	}
+	return diagnoser.Diagnose(ctx)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+// diagnoserDB is a database returning fixed check results
+type diagnoserDB struct {
+	db.Database
+	results []db.CheckResult
+}
+
+func (d *diagnoserDB) Diagnose(ctx context.Context) []db.CheckResult {
+	return d.results
+}
+
+func TestPrintDiagnostics(t *testing.T) {
+	results := []db.CheckResult{
+		{Name: "Config", Status: db.CheckPass, Detail: "loaded from defaults and environment"},
+		{Name: "Schema version", Status: db.CheckWarn, Detail: "not recorded"},
+		{Name: "Orphaned entries", Status: db.CheckFail, Detail: "2 found"},
+	}
+
+	tests := []struct {
+		name    string
+		format  outputFormat
+		results []db.CheckResult
+		want    string
+		summary string
+		err     string
+	}{
+		{
+			name:    "table",
+			format:  outputTable,
+			results: results,
+			want: "STATUS  CHECK             DETAIL\n" +
+				"PASS    Config            loaded from defaults and environment\n" +
+				"WARN    Schema version    not recorded\n" +
+				"FAIL    Orphaned entries  2 found\n",
+			summary: "1 passed, 1 warnings, 1 failed\n",
+			err:     "1 of 3 checks failed",
+		},
+		{
+			name:    "plain",
+			format:  outputPlain,
+			results: results[:2],
+			want:    "PASS\tConfig\tloaded from defaults and environment\nWARN\tSchema version\tnot recorded\n",
+			summary: "1 passed, 1 warnings, 0 failed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, summary bytes.Buffer
+			err := printDiagnostics(&out, &summary, tt.format, tt.results)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("printDiagnostics() error = %v, want containing %q", err, tt.err)
+				}
+			} else if err != nil {
+				t.Errorf("printDiagnostics() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("printDiagnostics() printed\n%s\nwant\n%s", out.String(), tt.want)
+			}
+			if summary.String() != tt.summary {
+				t.Errorf("summary = %q, want %q", summary.String(), tt.summary)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var out, summary bytes.Buffer
+		if err := printDiagnostics(&out, &summary, outputJSON, results); err == nil {
+			t.Error("printDiagnostics() error = nil, want failed checks")
+		}
+		var got []db.CheckResult
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("printed invalid JSON: %v\n%s", err, out.String())
+		}
+		if !reflect.DeepEqual(got, results) {
+			t.Errorf("printed %+v, want %+v", got, results)
+		}
+	})
+}
+
+func TestDiagnoseDatabase(t *testing.T) {
+	results := []db.CheckResult{{Name: "Tables", Status: db.CheckPass, Detail: "journals"}}
+
+	tests := []struct {
+		name     string
+		database db.Database
+		want     []db.CheckResult
+	}{
+		{
+			name:     "with checks",
+			database: &diagnoserDB{results: results},
+			want:     results,
+		},
+		{
+			name:     "without checks",
+			database: struct{ db.Database }{},
+			want:     []db.CheckResult{{Name: "Schema", Status: db.CheckWarn, Detail: "fake backend does not support schema checks"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diagnoseDatabase(context.Background(), "fake", tt.database); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diagnoseDatabase() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDiagnostics(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       globalOptions
+		compiled   string // Test is skipped if this backend is compiled in
+		wantLast   string
+		wantDetail string
+	}{
+		{
+			name:       "missing config file",
+			opts:       globalOptions{config: filepath.Join(t.TempDir(), "missing.toml")},
+			wantLast:   "Config",
+			wantDetail: "missing.toml",
+		},
+		{
+			name:       "invalid database type",
+			opts:       globalOptions{dbType: "mysql"},
+			wantLast:   "Config",
+			wantDetail: "invalid database type: mysql",
+		},
+		{
+			name:       "backend not compiled",
+			opts:       globalOptions{dbType: "psql"},
+			compiled:   "psql",
+			wantLast:   "Backend",
+			wantDetail: "psql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.compiled != "" && requireCapability(capabilityDatabase, tt.compiled) == nil {
+				t.Skipf("%s backend is compiled in", tt.compiled)
+			}
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("HOME", dir)
+			for _, name := range []string{"FIRN_CONFIG", "DATABASE_TYPE", "DATABASE_URI", "LOG_LEVEL"} {
+				t.Setenv(name, "")
+			}
+
+			results := runDiagnostics(context.Background(), tt.opts)
+			last := results[len(results)-1]
+			if last.Name != tt.wantLast || last.Status != db.CheckFail || !strings.Contains(last.Detail, tt.wantDetail) {
+				t.Errorf("runDiagnostics() stopped on %+v, want failed %s containing %q", last, tt.wantLast, tt.wantDetail)
+			}
+			for _, r := range results[:len(results)-1] {
+				if r.Status != db.CheckPass {
+					t.Errorf("check before failed one = %+v, want passed", r)
+				}
+			}
+		})
+	}
+}
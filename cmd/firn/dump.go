@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/dump"
+)
+
+var (
+	dumpCommand = &command{
+		name:    "dump",
+		summary: "Write all journals, entries and tags to a JSON archive",
+		setup:   withDatabase(dumpRun),
+	}
+	restoreCommand = &command{
+		name:    "restore",
+		usage:   "<file>",
+		summary: "Load a JSON archive written by dump, '-' reads standard input",
+		setup:   withDatabase(restoreRun),
+	}
+)
+
+func dumpRun(fs *flag.FlagSet) cliRunner {
+	out := fs.String("out", "-", "file to write archive to, '-' writes to standard output")
+	format := fs.String("format", "", "archive format: json or jsonl, by default json for .json files and jsonl otherwise")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 0 {
+			return errUsage
+		}
+
+		f := dump.Format(*format)
+		switch {
+		case f == "" && strings.EqualFold(filepath.Ext(*out), ".json"):
+			f = dump.FormatJSON
+		case f == "":
+			f = dump.FormatJSONL
+		case f != dump.FormatJSON && f != dump.FormatJSONL:
+			return fmt.Errorf("unknown archive format %q, expected json or jsonl", *format)
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "-" {
+			file, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+
+		counts, err := dump.Write(ctx, database, w, f)
+		if err != nil {
+			if *out != "-" {
+				os.Remove(*out)
+			}
+			return err
+		}
+
+		if *out != "-" {
+			fmt.Fprintf(os.Stderr, "Dumped %d journals, %d entries, %d tags and %d tag assignments to %s\n",
+				counts.Journals, counts.Entries, counts.Tags, counts.TagAssignments, *out)
+		}
+		return nil
+	}
+}
+
+func restoreRun(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	onConflict := fs.String("on-conflict", string(db.ConflictFail), "what to do with records which IDs exist: skip, overwrite or fail")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		policy, err := db.ParseConflictPolicy(*onConflict)
+		if err != nil {
+			return err
+		}
+
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		_, snapshot, err := dump.Read(r)
+		if err != nil {
+			return err
+		}
+
+		stats, err := database.Restore(ctx, snapshot, policy)
+		if err != nil {
+			return fmt.Errorf("restore is rolled back: %w", err)
+		}
+
+		rows := [][]string{}
+		for _, c := range []struct {
+			kind   string
+			counts db.RestoreCounts
+		}{
+			{"journals", stats.Journals},
+			{"entries", stats.Entries},
+			{"tags", stats.Tags},
+			{"tag assignments", stats.TagAssignments},
+		} {
+			rows = append(rows, []string{c.kind, fmt.Sprint(c.counts.Created), fmt.Sprint(c.counts.Overwritten), fmt.Sprint(c.counts.Skipped)})
+		}
+		return printRecords(os.Stdout, *format, []string{"RECORDS", "CREATED", "OVERWRITTEN", "SKIPPED"}, rows, stats)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+var entryCommands = []*command{
+	{name: "list", usage: "<journal>", summary: "List entries of a journal", setup: withDatabase(entryList)},
+	{name: "show", usage: "<journal> <entry>", summary: "Show an entry with its tags", setup: withDatabase(entryShow)},
+	{name: "add", usage: "<journal>", summary: "Add an entry to a journal", setup: withDatabase(entryAdd)},
+	{name: "edit", usage: "<journal> <entry>", summary: "Change title, content or tags of an entry", setup: withDatabase(entryEdit)},
+	{name: "rm", usage: "<journal> <entry>...", summary: "Remove entries", setup: withDatabase(entryRm)},
+}
+
+// entryWithTags is an entry printed with labels of its tags
+type entryWithTags struct {
+	kb.Entry
+	Tags []string `json:"tags"`
+}
+
+func printEntries(format outputFormat, entries []kb.Entry) error {
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{e.Id, oneLine(e.Title), formatTime(format, e.CreatedAt), formatTime(format, e.UpdatedAt)})
+	}
+	if entries == nil {
+		entries = []kb.Entry{}
+	}
+	return printRecords(os.Stdout, format, []string{"ID", "TITLE", "CREATED", "UPDATED"}, rows, entries)
+}
+
+// printEntry prints entry fields followed by its content, plain output
+// is the content only so it could be piped
+func printEntry(ctx context.Context, database db.Database, format outputFormat, entry *kb.Entry) error {
+	tags, err := database.ListEntryTags(ctx, entry.JournalId, entry.Id)
+	if err != nil {
+		return err
+	}
+	labels := make([]string, 0, len(tags))
+	for _, t := range tags {
+		labels = append(labels, t.Label)
+	}
+
+	switch format {
+	case outputJSON:
+		return printRecords(os.Stdout, format, nil, nil, entryWithTags{Entry: *entry, Tags: labels})
+	case outputPlain:
+		_, err := fmt.Fprintln(os.Stdout, strings.TrimRight(entry.Content, "\n"))
+		return err
+	}
+
+	rows := [][]string{
+		{"ID:", entry.Id},
+		{"Journal:", entry.JournalId},
+		{"Title:", oneLine(entry.Title)},
+		{"Tags:", strings.Join(labels, ", ")},
+		{"Created:", formatTime(format, entry.CreatedAt)},
+		{"Updated:", formatTime(format, entry.UpdatedAt)},
+	}
+	if err := printRecords(os.Stdout, outputTable, nil, rows, nil); err != nil {
+		return err
+	}
+	if entry.Content != "" {
+		_, err = fmt.Fprintf(os.Stdout, "\n%s\n", strings.TrimRight(entry.Content, "\n"))
+	}
+	return err
+}
+
+// getEntry loads entry of a journal given by its ID or name
+func getEntry(ctx context.Context, database db.Database, journalRef, entryId string) (*kb.Entry, error) {
+	journal, err := resolveJournal(ctx, database, journalRef)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := database.GetEntryById(ctx, journal.Id, entryId)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %s", db.ErrEntryNotFound, entryId)
+	}
+	return entry, nil
+}
+
+// readContent returns content given inline or read from file, "-" reads
+// standard input
+func readContent(content, path string) (string, error) {
+	if path == "" {
+		return content, nil
+	}
+	if content != "" {
+		return "", errors.New("-content and -content-file could not be used together")
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+	return string(data), nil
+}
+
+// flagsSet returns names of flags given in command line
+func flagsSet(fs *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+func entryList(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	orderBy, desc := sortFlags(fs)
+	limit := fs.Int("limit", 0, "maximum number of entries, 0 lists all")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		order, err := parseOrderBy(*orderBy)
+		if err != nil {
+			return err
+		}
+
+		journal, err := resolveJournal(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		entries, err := listAllEntries(ctx, database, journal.Id, order, *desc, *limit)
+		if err != nil {
+			return err
+		}
+		return printEntries(*format, entries)
+	}
+}
+
+func entryShow(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 2 {
+			return errUsage
+		}
+
+		entry, err := getEntry(ctx, database, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return printEntry(ctx, database, *format, entry)
+	}
+}
+
+func entryAdd(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	title := fs.String("title", "", "entry title")
+	content := fs.String("content", "", "entry content")
+	contentFile := fs.String("content-file", "", "read entry content from file, - reads standard input")
+	tags := fs.String("tags", "", "comma separated tags, missing tags are created")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 || *title == "" {
+			return errUsage
+		}
+
+		journal, err := resolveJournal(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		text, err := readContent(*content, *contentFile)
+		if err != nil {
+			return err
+		}
+
+		entry, err := database.CreateEntry(ctx, journal.Id, *title, text)
+		if err != nil {
+			return err
+		}
+
+		if *tags != "" {
+			if err := db.SyncEntryTags(ctx, database, journal.Id, entry.Id, strings.Split(*tags, ",")); err != nil {
+				return fmt.Errorf("entry %s is created, but tags are not assigned: %w", entry.Id, err)
+			}
+		}
+
+		return printEntry(ctx, database, *format, entry)
+	}
+}
+
+func entryEdit(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	title := fs.String("title", "", "new entry title")
+	content := fs.String("content", "", "new entry content")
+	contentFile := fs.String("content-file", "", "read new entry content from file, - reads standard input")
+	tags := fs.String("tags", "", "comma separated tags replacing assigned ones, empty removes all")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		set := flagsSet(fs)
+		if len(args) != 2 || !(set["title"] || set["content"] || set["content-file"] || set["tags"]) {
+			return errUsage
+		}
+
+		entry, err := getEntry(ctx, database, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if set["title"] || set["content"] || set["content-file"] {
+			newTitle, newContent := entry.Title, entry.Content
+			if set["title"] {
+				if *title == "" {
+					return errors.New("entry title could not be empty")
+				}
+				newTitle = *title
+			}
+			if set["content"] || set["content-file"] {
+				if newContent, err = readContent(*content, *contentFile); err != nil {
+					return err
+				}
+			}
+
+			if entry, err = database.UpdateEntry(ctx, entry.JournalId, entry.Id, newTitle, newContent); err != nil {
+				return err
+			}
+		}
+
+		if set["tags"] {
+			if err := db.SyncEntryTags(ctx, database, entry.JournalId, entry.Id, strings.Split(*tags, ",")); err != nil {
+				return err
+			}
+		}
+
+		return printEntry(ctx, database, *format, entry)
+	}
+}
+
+func entryRm(fs *flag.FlagSet) cliRunner {
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) < 2 {
+			return errUsage
+		}
+
+		journal, err := resolveJournal(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		for _, id := range args[1:] {
+			if err := database.DeleteEntry(ctx, journal.Id, id); err != nil {
+				return fmt.Errorf("entry %s: %w", id, err)
+			}
+			fmt.Fprintf(os.Stderr, "Removed entry %s\n", id)
+		}
+		return nil
+	}
+}
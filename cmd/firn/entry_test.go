@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestEntryCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(fs *flag.FlagSet) cliRunner
+		args       []string
+		wantOut    string
+		wantStderr string
+		err        string
+		check      func(t *testing.T, d *fakeDB)
+	}{
+		{
+			name:  "list table",
+			setup: entryList,
+			args:  []string{"Personal"},
+			wantOut: "ID  TITLE        CREATED           UPDATED\n" +
+				"e1  First        " + cliTableTime + "  " + cliTableTime + "\n" +
+				"e2  Second line  " + cliTableTime + "  " + cliTableTime + "\n",
+		},
+		{
+			name:  "list plain",
+			setup: entryList,
+			args:  []string{"j1", "-o", "plain"},
+			wantOut: "e1\tFirst\t2024-01-02T10:00:00Z\t2024-01-02T10:00:00Z\n" +
+				"e2\tSecond line\t2024-01-02T10:00:00Z\t2024-01-02T10:00:00Z\n",
+		},
+		{
+			name:    "list json of empty journal",
+			setup:   entryList,
+			args:    []string{"Work", "-o", "json"},
+			wantOut: "[]\n",
+		},
+		{
+			name:  "list without journal",
+			setup: entryList,
+			err:   errUsage.Error(),
+		},
+		{
+			name:  "show table",
+			setup: entryShow,
+			args:  []string{"Personal", "e1"},
+			wantOut: "ID:       e1\n" +
+				"Journal:  j1\n" +
+				"Title:    First\n" +
+				"Tags:     work\n" +
+				"Created:  " + cliTableTime + "\n" +
+				"Updated:  " + cliTableTime + "\n" +
+				"\nText of first\n",
+		},
+		{
+			name:    "show plain",
+			setup:   entryShow,
+			args:    []string{"Personal", "e1", "-o", "plain"},
+			wantOut: "Text of first\n",
+		},
+		{
+			name:  "show json",
+			setup: entryShow,
+			args:  []string{"Personal", "e2", "-o", "json"},
+			wantOut: `{
+  "id": "e2",
+  "journal_id": "j1",
+  "title": "Second\tline",
+  "content": "",
+  "created_at": "2024-01-02T10:00:00Z",
+  "updated_at": "2024-01-02T10:00:00Z",
+  "tags": []
+}
+`,
+		},
+		{
+			name:  "show of other journal",
+			setup: entryShow,
+			args:  []string{"Work", "e1"},
+			err:   "entry not found: e1",
+		},
+		{
+			name:       "rm stops on error",
+			setup:      entryRm,
+			args:       []string{"Personal", "e2", "e3", "e1"},
+			wantStderr: "Removed entry e2\n",
+			err:        "entry e3: entry not found",
+			check: func(t *testing.T, d *fakeDB) {
+				if len(d.entries) != 1 || d.entries[0].Id != "e1" {
+					t.Errorf("left entries %+v, want e1", d.entries)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := newFakeDB()
+			out, stderr, err := runCli(t, tt.setup, database, tt.args)
+			checkRunErr(t, err, tt.err)
+			if out != tt.wantOut {
+				t.Errorf("stdout =\n%s\nwant\n%s", out, tt.wantOut)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+			if tt.check != nil {
+				tt.check(t, database)
+			}
+		})
+	}
+}
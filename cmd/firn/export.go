@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/markdown"
+)
+
+var exportCommands = []*command{
+	{name: "markdown", summary: "Export journals to a directory of Markdown files", setup: withDatabase(exportMarkdown)},
+}
+
+func exportMarkdown(fs *flag.FlagSet) cliRunner {
+	out := fs.String("out", "", "directory to write journal directories to, created when missing")
+	journalRef := fs.String("journal", "", "export only this journal, by ID or name")
+	fs.StringVar(journalRef, "j", "", "shorthand for -journal")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 0 || *out == "" {
+			return errUsage
+		}
+
+		var journalIds []string
+		if *journalRef != "" {
+			journal, err := resolveJournal(ctx, database, *journalRef)
+			if err != nil {
+				return err
+			}
+			journalIds = append(journalIds, journal.Id)
+		}
+
+		stats, err := markdown.Export(ctx, database, *out, journalIds)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d entries of %d journals to %s\n", stats.Entries, stats.Journals, *out)
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/importer"
+	"github.com/kompotkot/firn/pkg/markdown"
+)
+
+var importCommands = append([]*command{
+	{name: "markdown", usage: "<dir>", summary: "Import a directory of Markdown files, like an Obsidian vault", setup: withDatabase(importMarkdown)},
+}, importerCommands()...)
+
+// importerCommands returns a command per registered importer
+func importerCommands() []*command {
+	var commands []*command
+	for _, name := range importer.GetAvailableFormats() {
+		imp, _ := importer.Get(name)
+		commands = append(commands, &command{
+			name:    name,
+			usage:   "<path>",
+			summary: "Import " + imp.GetDescription(),
+			setup:   withDatabase(importWith(imp)),
+		})
+	}
+	return commands
+}
+
+// importWith runs import of export read by imp
+func importWith(imp importer.Importer) func(fs *flag.FlagSet) cliRunner {
+	return func(fs *flag.FlagSet) cliRunner {
+		format := outputFlag(fs)
+		journalName := fs.String("journal", "", "import all entries to journal with this name instead of names from export")
+		fs.StringVar(journalName, "j", "", "shorthand for -journal")
+		dryRun := fs.Bool("dry-run", false, "print journals and numbers of their entries without importing")
+
+		return func(ctx context.Context, database db.Database, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			journals, err := imp.Read(args[0])
+			if err != nil {
+				return err
+			}
+			if *journalName != "" {
+				var entries []importer.Entry
+				for _, j := range journals {
+					entries = append(entries, j.Entries...)
+				}
+				journals = []importer.Journal{{Name: *journalName, Entries: entries}}
+			}
+
+			if *dryRun {
+				type journalSummary struct {
+					Name    string `json:"name"`
+					Entries int    `json:"entries"`
+				}
+				summaries := make([]journalSummary, len(journals))
+				rows := make([][]string, len(journals))
+				for i, j := range journals {
+					summaries[i] = journalSummary{Name: j.Name, Entries: len(j.Entries)}
+					rows[i] = []string{j.Name, fmt.Sprint(len(j.Entries))}
+				}
+				return printRecords(os.Stdout, *format, []string{"JOURNAL", "ENTRIES"}, rows, summaries)
+			}
+
+			stats, err := importer.Import(ctx, database, imp, journals)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Imported %d new journals, %d new entries, %d updated entries, %d moved entries, %d unchanged entries\n",
+				stats.CreatedJournals, stats.CreatedEntries, stats.UpdatedEntries, stats.MovedEntries, stats.UnchangedEntries)
+			return nil
+		}
+	}
+}
+
+// planRecord is a change of import plan printed to user
+type planRecord struct {
+	Action markdown.Action `json:"action"`
+	Kind   string          `json:"kind"`
+	Id     string          `json:"id"`
+	Name   string          `json:"name"`
+	Source string          `json:"source"`
+	Tags   []string        `json:"tags,omitempty"`
+
+	FromJournal string `json:"from_journal,omitempty"`
+}
+
+// printPlan prints journals and entries which import creates or updates
+func printPlan(format outputFormat, plan *markdown.ImportPlan) error {
+	records := []planRecord{}
+	for _, j := range plan.Journals {
+		if j.Action != markdown.ActionUnchanged {
+			records = append(records, planRecord{Action: j.Action, Kind: "journal", Id: j.Journal.Id, Name: j.Journal.Name, Source: j.Source})
+		}
+		for _, e := range j.Entries {
+			if e.Action != markdown.ActionUnchanged {
+				records = append(records, planRecord{Action: e.Action, Kind: "entry", Id: e.Entry.Id, Name: e.Entry.Title, Source: e.Source, Tags: e.Tags, FromJournal: e.FromJournal})
+			}
+		}
+	}
+
+	rows := make([][]string, len(records))
+	for i, r := range records {
+		rows[i] = []string{string(r.Action), r.Kind, oneLine(r.Name), r.Source, strings.Join(r.Tags, ",")}
+	}
+	return printRecords(os.Stdout, format, []string{"ACTION", "KIND", "NAME", "SOURCE", "TAGS"}, rows, records)
+}
+
+// confirm asks user a yes or no question on terminal
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func importMarkdown(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "print the plan without importing")
+	yes := fs.Bool("yes", false, "import without asking for confirmation")
+	fs.BoolVar(yes, "y", false, "shorthand for -yes")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+
+		plan, err := markdown.PlanImport(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := printPlan(*format, plan); err != nil {
+			return err
+		}
+
+		createdJournals, createdEntries := plan.Count(markdown.ActionCreate)
+		updatedJournals, updatedEntries := plan.Count(markdown.ActionUpdate)
+		unchangedJournals, unchangedEntries := plan.Count(markdown.ActionUnchanged)
+		fmt.Fprintf(os.Stderr, "Journals: %d to create, %d to update, %d unchanged\n", createdJournals, updatedJournals, unchangedJournals)
+		_, movedEntries := plan.Count(markdown.ActionMove)
+		fmt.Fprintf(os.Stderr, "Entries: %d to create, %d to update, %d to move, %d unchanged\n", createdEntries, updatedEntries, movedEntries, unchangedEntries)
+		if movedEntries > 0 {
+			fmt.Fprintf(os.Stderr, "Entries to move have IDs of entries in other journals, they are moved and overwritten by imported files\n")
+		}
+
+		switch {
+		case createdJournals+createdEntries+updatedJournals+updatedEntries+movedEntries == 0:
+			fmt.Fprintln(os.Stderr, "Nothing to import")
+			return nil
+		case *dryRun:
+			return nil
+		case !*yes && stdinPiped():
+			return errors.New("import is not confirmed, run with -yes to import without a terminal")
+		case !*yes && !confirm("Import?"):
+			fmt.Fprintln(os.Stderr, "Import cancelled")
+			return nil
+		}
+
+		if err := plan.Apply(ctx, database); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Imported %d journals and %d entries\n", createdJournals+updatedJournals, createdEntries+updatedEntries+movedEntries)
+		return nil
+	}
+}
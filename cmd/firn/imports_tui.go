@@ -5,13 +5,20 @@ package main
 import (
 	"context"
 
+	"github.com/kompotkot/firn/internal/types"
 	"github.com/kompotkot/firn/pkg/db"
 
 	"github.com/kompotkot/firn/pkg/tui" // Import to register Terminal User Interface
 )
 
-func ModuleTui(ctx context.Context, database db.Database) error {
-	return tui.ShowTui(ctx, database)
+func ModuleTui(ctx context.Context, database db.Database, tc types.TuiConfig) error {
+	return tui.ShowTui(ctx, database, tui.Options{
+		Theme:           tc.Theme,
+		ThemeFile:       tc.ThemeFile,
+		KeymapFile:      tc.KeymapFile,
+		RefreshInterval: tc.RefreshInterval,
+		Debug:           tc.Debug,
+	})
 }
 
 func init() {
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+var journalCommands = []*command{
+	{name: "list", usage: "", summary: "List journals", setup: withDatabase(journalList)},
+	{name: "create", usage: "<name>", summary: "Create a journal", setup: withDatabase(journalCreate)},
+	{name: "rename", usage: "<journal> <name>", summary: "Rename a journal", setup: withDatabase(journalRename)},
+	{name: "delete", usage: "<journal>", summary: "Delete a journal with its entries", setup: withDatabase(journalDelete)},
+}
+
+// printJournals prints journals in the given format
+func printJournals(format outputFormat, journals []kb.Journal) error {
+	rows := make([][]string, 0, len(journals))
+	for _, j := range journals {
+		rows = append(rows, []string{j.Id, j.Name, formatTime(format, j.CreatedAt), formatTime(format, j.UpdatedAt)})
+	}
+	if journals == nil {
+		journals = []kb.Journal{}
+	}
+	return printRecords(os.Stdout, format, []string{"ID", "NAME", "CREATED", "UPDATED"}, rows, journals)
+}
+
+func printJournal(format outputFormat, journal *kb.Journal) error {
+	if format == outputJSON {
+		return printRecords(os.Stdout, format, nil, nil, journal)
+	}
+	return printJournals(format, []kb.Journal{*journal})
+}
+
+func journalList(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	orderBy, desc := sortFlags(fs)
+	limit := fs.Int("limit", 0, "maximum number of journals, 0 lists all")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 0 {
+			return errUsage
+		}
+		order, err := parseOrderBy(*orderBy)
+		if err != nil {
+			return err
+		}
+
+		journals, err := listAllJournals(ctx, database, order, *desc, *limit)
+		if err != nil {
+			return err
+		}
+		return printJournals(*format, journals)
+	}
+}
+
+func journalCreate(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 || args[0] == "" {
+			return errUsage
+		}
+
+		journal, err := database.CreateJournal(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return printJournal(*format, journal)
+	}
+}
+
+func journalRename(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 2 || args[1] == "" {
+			return errUsage
+		}
+
+		journal, err := resolveJournal(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		journal, err = database.RenameJournal(ctx, journal.Id, args[1])
+		if err != nil {
+			return err
+		}
+		return printJournal(*format, journal)
+	}
+}
+
+func journalDelete(fs *flag.FlagSet) cliRunner {
+	force := fs.Bool("force", false, "delete journal even if it has entries")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+
+		journal, err := resolveJournal(ctx, database, args[0])
+		if err != nil {
+			return err
+		}
+
+		if !*force {
+			entries, err := database.ListEntries(ctx, journal.Id, db.OrderByUpdated, false, 1, 0)
+			if err != nil {
+				return err
+			}
+			if len(entries) > 0 {
+				return fmt.Errorf("journal %q has entries, use -force to delete them too", journal.Name)
+			}
+		}
+
+		if err := database.DeleteJournal(ctx, journal.Id); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Deleted journal %q (%s)\n", journal.Name, journal.Id)
+		return nil
+	}
+}
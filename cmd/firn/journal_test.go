@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestJournalCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(fs *flag.FlagSet) cliRunner
+		args       []string
+		wantOut    string
+		wantStderr string
+		err        string
+		check      func(t *testing.T, d *fakeDB)
+	}{
+		{
+			name:  "list table",
+			setup: journalList,
+			wantOut: "ID  NAME      CREATED           UPDATED\n" +
+				"j1  Personal  " + cliTableTime + "  " + cliTableTime + "\n" +
+				"j2  Work      " + cliTableTime + "  " + cliTableTime + "\n",
+		},
+		{
+			name:    "list plain",
+			setup:   journalList,
+			args:    []string{"-o", "plain", "-limit", "1"},
+			wantOut: "j1\tPersonal\t2024-01-02T10:00:00Z\t2024-01-02T10:00:00Z\n",
+		},
+		{
+			name:  "list json",
+			setup: journalList,
+			args:  []string{"--output=json", "-limit", "1"},
+			wantOut: `[
+  {
+    "id": "j1",
+    "name": "Personal",
+    "created_at": "2024-01-02T10:00:00Z",
+    "updated_at": "2024-01-02T10:00:00Z"
+  }
+]
+`,
+		},
+		{
+			name:  "list unknown sort",
+			setup: journalList,
+			args:  []string{"-sort", "size"},
+			err:   `unknown sort field "size", expected updated, created or name`,
+		},
+		{
+			name:    "create json",
+			setup:   journalCreate,
+			args:    []string{"Ideas", "-o", "json"},
+			wantOut: "{\n  \"id\": \"j-new1\",\n  \"name\": \"Ideas\",\n  \"created_at\": \"2024-01-02T10:00:00Z\",\n  \"updated_at\": \"2024-01-02T10:00:00Z\"\n}\n",
+		},
+		{
+			name:  "create without name",
+			setup: journalCreate,
+			args:  []string{""},
+			err:   errUsage.Error(),
+		},
+		{
+			name:    "rename by name",
+			setup:   journalRename,
+			args:    []string{"Work", "Job", "-o", "plain"},
+			wantOut: "j2\tJob\t2024-01-02T10:00:00Z\t2024-01-02T10:00:00Z\n",
+		},
+		{
+			name:  "rename unknown",
+			setup: journalRename,
+			args:  []string{"Diary", "Job"},
+			err:   "journal not found: Diary",
+		},
+		{
+			name:  "delete with entries",
+			setup: journalDelete,
+			args:  []string{"Personal"},
+			err:   `journal "Personal" has entries, use -force to delete them too`,
+		},
+		{
+			name:       "delete forced",
+			setup:      journalDelete,
+			args:       []string{"j1", "-force"},
+			wantStderr: "Deleted journal \"Personal\" (j1)\n",
+			check: func(t *testing.T, d *fakeDB) {
+				if len(d.journals) != 1 || len(d.entries) != 0 {
+					t.Errorf("left %d journals and %d entries, want 1 and 0", len(d.journals), len(d.entries))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := newFakeDB()
+			out, stderr, err := runCli(t, tt.setup, database, tt.args)
+			checkRunErr(t, err, tt.err)
+			if out != tt.wantOut {
+				t.Errorf("stdout =\n%s\nwant\n%s", out, tt.wantOut)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+			if tt.check != nil {
+				tt.check(t, database)
+			}
+		})
+	}
+}
@@ -2,102 +2,161 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
-	"os/signal"
-	"syscall"
-	"time"
-
 	"os"
 
-	"github.com/kompotkot/firn/internal/config"
 	"github.com/kompotkot/firn/internal/logger"
+	"github.com/kompotkot/firn/internal/types"
 	"github.com/kompotkot/firn/pkg/db"
 )
 
 const FIRN_VERSION = "0.0.1"
 
 var (
-	moduleTui func(context.Context, db.Database) error
+	moduleTui func(context.Context, db.Database, types.TuiConfig) error
 )
 
-func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: firn <command> [args]")
-		fmt.Println("Available commands: server, tui, version")
-		os.Exit(1)
+// rootCommand builds tree of firn commands
+func rootCommand() *command {
+	root := &command{
+		name:    "firn",
+		summary: "firn keeps journals with tagged entries and serves them over REST API or in the terminal.",
+		commands: []*command{
+			{name: "server", summary: "Run REST API server", setup: moduleCommand("server")},
+			{name: "tui", summary: "Open journals in terminal UI", setup: moduleCommand("tui")},
+			addCommand,
+			{name: "journal", summary: "Manage journals", commands: journalCommands},
+			{name: "entry", summary: "Manage journal entries", commands: entryCommands},
+			{name: "tag", summary: "Manage tags", commands: tagCommands},
+			{name: "export", summary: "Export journals to other formats", commands: exportCommands},
+			{name: "import", summary: "Import journals from other formats", commands: importCommands},
+			dumpCommand,
+			restoreCommand,
+			doctorCommand,
+			{name: "version", summary: "Print version, with -verbose also build info and compiled backends", setup: versionCommand},
+		},
 	}
 
-	var rModule string
+	root.commands = append(root.commands,
+		&command{name: "completion", usage: "bash|zsh|fish", summary: "Print shell completion script", setup: completionCommand},
+		&command{name: "help", usage: "[command]...", summary: "Show help of a command", setup: helpCommand(root)},
+	)
 
-	switch os.Args[1] {
-	case "server":
-		rModule = "server"
-	case "tui":
-		// TODO(kompotkot): Check if build with tui
-		rModule = "tui"
-	case "version":
-		fmt.Println(FIRN_VERSION)
-		return
-	default:
-		fmt.Printf("Unknown command: %s\n", os.Args[1])
-		os.Exit(1)
-	}
+	return root
+}
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		fmt.Printf("Failed to load config: %v\n", err)
-		os.Exit(1)
-	}
+func main() {
+	root := rootCommand()
 
-	// Initialize logger
-	log := logger.New(cfg.Logger)
-	log.Info("Logger initialized")
-
-	// Initialize database connection using registry
-	log.Info("Initializing database connection")
-	database, err := db.CreateDatabase(
-		cfg.Database.Type,
-		cfg.Database.URI,
-		cfg.Database.MaxConns,
-		int64(cfg.Database.ConnMaxLifetime),
-	)
-	if err != nil {
-		log.Error("Failed to initialize database connection", "error", err)
-		os.Exit(1)
+	// Words typed in shell are passed as is, they could contain unknown flags
+	if len(os.Args) > 1 && os.Args[1] == completeCommandName {
+		printCompletions(root, os.Args[2:])
+		return
 	}
 
-	// Test database connection
-	if err := database.TestConnection(context.Background()); err != nil {
-		log.Error("Failed to test database connection", "error", err)
-		os.Exit(1)
-	}
-	log.Info("Database connection established successfully")
+	os.Exit(runCommands(root, os.Args[1:]))
+}
 
-	// Create context for graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+func versionCommand(fs *flag.FlagSet) action {
+	verbose := fs.Bool("verbose", false, "print build info and compiled database backends and frontends")
+	fs.BoolVar(verbose, "v", false, "shorthand for -verbose")
 
-	switch rModule {
-	case "server":
-		// TODO(kompotkot): Initialize server in go-routine
-		fmt.Println("Not implemented yet")
-		return
-	case "tui":
-		log.Info("Starting TUI")
-		if err := moduleTui(ctx, database); err != nil {
-			log.Error("TUI error", "error", err)
+	return func(ctx context.Context, opts globalOptions, args []string) error {
+		if len(args) != 0 {
+			return errUsage
+		}
+		if *verbose {
+			printBuildInfo(os.Stdout)
+			return nil
 		}
-		stop()
+		fmt.Println(FIRN_VERSION)
+		return nil
 	}
+}
 
-	// Wait for shutdown signal
-	<-ctx.Done()
-	log.Info("Received shutdown signal, starting graceful shutdown")
-
-	// Gracefully close database connection
-	log.Info("Closing database connection")
-	database.Close()
+func helpCommand(root *command) func(fs *flag.FlagSet) action {
+	return func(fs *flag.FlagSet) action {
+		return func(ctx context.Context, opts globalOptions, args []string) error {
+			path, err := findCommand(root, args)
+			if err != nil {
+				return err
+			}
+			printHelp(os.Stdout, root, path)
+			return nil
+		}
+	}
+}
 
-	log.Info("Application shutdown complete")
+// moduleCommand runs long-living server or TUI module until it is finished
+// or shutdown signal is received
+func moduleCommand(rModule string) func(fs *flag.FlagSet) action {
+	return func(fs *flag.FlagSet) action {
+		return func(ctx context.Context, opts globalOptions, args []string) error {
+			if len(args) != 0 {
+				return errUsage
+			}
+			if err := requireCapability(capabilityFrontend, rModule); err != nil {
+				return err
+			}
+
+			// Load configuration
+			cfg, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			// Initialize logger
+			log := logger.New(cfg.Logger)
+			log.Info("Logger initialized")
+
+			// Initialize database connection using registry
+			log.Info("Initializing database connection")
+			database, err := db.CreateDatabase(
+				cfg.Database.Type,
+				cfg.Database.URI,
+				cfg.Database.MaxConns,
+				int64(cfg.Database.ConnMaxLifetime),
+			)
+			if err != nil {
+				log.Error("Failed to initialize database connection", "error", err)
+				os.Exit(1)
+			}
+
+			// Test database connection
+			if err := database.TestConnection(context.Background()); err != nil {
+				log.Error("Failed to test database connection", "error", err)
+				os.Exit(1)
+			}
+			log.Info("Database connection established successfully")
+
+			// Context is cancelled on graceful shutdown
+			ctx, stop := context.WithCancel(ctx)
+			defer stop()
+
+			switch rModule {
+			case "server":
+				// TODO(kompotkot): Initialize server in go-routine
+				fmt.Println("Not implemented yet")
+				return nil
+			case "tui":
+				log.Info("Starting TUI")
+				if err := moduleTui(ctx, database, cfg.Tui); err != nil {
+					log.Error("TUI error", "error", err)
+				}
+				stop()
+			}
+
+			// Wait for shutdown signal
+			<-ctx.Done()
+			log.Info("Received shutdown signal, starting graceful shutdown")
+
+			// Gracefully close database connection
+			log.Info("Closing database connection")
+			database.Close()
+
+			log.Info("Application shutdown complete")
+			return nil
+		}
+	}
 }
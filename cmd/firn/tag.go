@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+var tagCommands = []*command{
+	{name: "list", usage: "", summary: "List tags, or tags of an entry", setup: withDatabase(tagList)},
+	{name: "add", usage: "<label>...", summary: "Create tags", setup: withDatabase(tagAdd)},
+	{name: "rm", usage: "<label>...", summary: "Remove tags from all entries and delete them", setup: withDatabase(tagRm)},
+	{name: "assign", usage: "<journal> <entry> <label>...", summary: "Assign tags to an entry, missing tags are created", setup: withDatabase(tagAssign)},
+}
+
+func printTags(format outputFormat, tags []kb.Tag) error {
+	rows := make([][]string, 0, len(tags))
+	for _, t := range tags {
+		rows = append(rows, []string{t.Id, t.Label})
+	}
+	if tags == nil {
+		tags = []kb.Tag{}
+	}
+	return printRecords(os.Stdout, format, []string{"ID", "LABEL"}, rows, tags)
+}
+
+func tagList(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+	journalRef := fs.String("journal", "", "journal of the entry")
+	entryId := fs.String("entry", "", "list only tags assigned to this entry")
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) != 0 || (*entryId == "") != (*journalRef == "") {
+			return errUsage
+		}
+
+		if *entryId == "" {
+			tags, err := database.ListTags(ctx, nil)
+			if err != nil {
+				return err
+			}
+			return printTags(*format, tags)
+		}
+
+		entry, err := getEntry(ctx, database, *journalRef, *entryId)
+		if err != nil {
+			return err
+		}
+		tags, err := database.ListEntryTags(ctx, entry.JournalId, entry.Id)
+		if err != nil {
+			return err
+		}
+		return printTags(*format, tags)
+	}
+}
+
+func tagAdd(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		labels := db.NormalizeTagLabels(args)
+		if len(labels) == 0 {
+			return errUsage
+		}
+
+		tags, err := database.CreateTags(ctx, labels)
+		if err != nil {
+			return err
+		}
+		return printTags(*format, tags)
+	}
+}
+
+func tagRm(fs *flag.FlagSet) cliRunner {
+	return func(ctx context.Context, database db.Database, args []string) error {
+		labels := db.NormalizeTagLabels(args)
+		if len(labels) == 0 {
+			return errUsage
+		}
+
+		tags, err := database.ListTags(ctx, labels)
+		if err != nil {
+			return err
+		}
+
+		found := make(map[string]bool, len(tags))
+		ids := make([]string, 0, len(tags))
+		for _, t := range tags {
+			found[t.Label] = true
+			ids = append(ids, t.Id)
+		}
+		var missing []string
+		for _, label := range labels {
+			if !found[label] {
+				missing = append(missing, label)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("unknown tags: %s", strings.Join(missing, ", "))
+		}
+
+		if err := database.DeleteTags(ctx, ids); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Removed tags: %s\n", strings.Join(labels, ", "))
+		return nil
+	}
+}
+
+func tagAssign(fs *flag.FlagSet) cliRunner {
+	format := outputFlag(fs)
+
+	return func(ctx context.Context, database db.Database, args []string) error {
+		if len(args) < 3 {
+			return errUsage
+		}
+		labels := db.NormalizeTagLabels(args[2:])
+		if len(labels) == 0 {
+			return errUsage
+		}
+
+		entry, err := getEntry(ctx, database, args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		tags, err := database.CreateTags(ctx, labels)
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(tags))
+		for _, t := range tags {
+			ids = append(ids, t.Id)
+		}
+
+		if err := database.AssignTagsToEntry(ctx, entry.JournalId, entry.Id, ids); err != nil {
+			return err
+		}
+
+		assigned, err := database.ListEntryTags(ctx, entry.JournalId, entry.Id)
+		if err != nil {
+			return err
+		}
+		return printTags(*format, assigned)
+	}
+}
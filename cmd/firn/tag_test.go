@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestTagCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(fs *flag.FlagSet) cliRunner
+		args       []string
+		wantOut    string
+		wantStderr string
+		err        string
+		check      func(t *testing.T, d *fakeDB)
+	}{
+		{
+			name:    "list table",
+			setup:   tagList,
+			wantOut: "ID  LABEL\nt1  work\nt2  idea\n",
+		},
+		{
+			name:    "list plain of entry",
+			setup:   tagList,
+			args:    []string{"-journal", "Personal", "-entry", "e1", "-o", "plain"},
+			wantOut: "t1\twork\n",
+		},
+		{
+			name:    "list json of untagged entry",
+			setup:   tagList,
+			args:    []string{"-journal", "j1", "-entry", "e2", "-o", "json"},
+			wantOut: "[]\n",
+		},
+		{
+			name:  "list entry without journal",
+			setup: tagList,
+			args:  []string{"-entry", "e1"},
+			err:   errUsage.Error(),
+		},
+		{
+			name:    "add keeps existing",
+			setup:   tagAdd,
+			args:    []string{" idea", "home", "idea", "-o", "plain"},
+			wantOut: "t2\tidea\nt-new1\thome\n",
+		},
+		{
+			name:       "rm",
+			setup:      tagRm,
+			args:       []string{"work "},
+			wantStderr: "Removed tags: work\n",
+			check: func(t *testing.T, d *fakeDB) {
+				if len(d.tags) != 1 || len(d.assigned["e1"]) != 0 {
+					t.Errorf("left tags %+v and assignments %v, want only idea", d.tags, d.assigned)
+				}
+			},
+		},
+		{
+			name:  "rm unknown",
+			setup: tagRm,
+			args:  []string{"work", "home", "later"},
+			err:   "unknown tags: home, later",
+			check: func(t *testing.T, d *fakeDB) {
+				if len(d.tags) != 2 {
+					t.Errorf("left tags %+v, want none deleted", d.tags)
+				}
+			},
+		},
+		{
+			name:  "rm without labels",
+			setup: tagRm,
+			args:  []string{" "},
+			err:   errUsage.Error(),
+		},
+		{
+			name:  "assign",
+			setup: tagAssign,
+			args:  []string{"Personal", "e1", "idea", "home", "work", "-o", "json"},
+			wantOut: `[
+  {
+    "id": "t1",
+    "label": "work"
+  },
+  {
+    "id": "t2",
+    "label": "idea"
+  },
+  {
+    "id": "t-new1",
+    "label": "home"
+  }
+]
+`,
+			check: func(t *testing.T, d *fakeDB) {
+				if want := []string{"t1", "t2", "t-new1"}; !reflect.DeepEqual(d.assigned["e1"], want) {
+					t.Errorf("assigned %v, want %v", d.assigned["e1"], want)
+				}
+			},
+		},
+		{
+			name:  "assign to missing entry",
+			setup: tagAssign,
+			args:  []string{"Personal", "e3", "idea"},
+			err:   "entry not found: e3",
+			check: func(t *testing.T, d *fakeDB) {
+				if len(d.assigned) != 1 {
+					t.Errorf("assignments = %v, want unchanged", d.assigned)
+				}
+			},
+		},
+		{
+			name:  "assign without labels",
+			setup: tagAssign,
+			args:  []string{"Personal", "e1"},
+			err:   errUsage.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := newFakeDB()
+			out, stderr, err := runCli(t, tt.setup, database, tt.args)
+			checkRunErr(t, err, tt.err)
+			if out != tt.wantOut {
+				t.Errorf("stdout =\n%s\nwant\n%s", out, tt.wantOut)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+			if tt.check != nil {
+				tt.check(t, database)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Environment variables read by Load
+var configEnv = []string{
+	"FIRN_CONFIG", "LOG_LEVEL", "LOG_FORMAT",
+	"DATABASE_TYPE", "DATABASE_URI", "DATABASE_MAX_OPEN_CONNS", "DATABASE_CONN_MAX_LIFETIME_SEC",
+	"SERVER_HOST", "SERVER_PORT",
+	"TUI_THEME", "TUI_THEME_FILE", "TUI_KEYMAP_FILE", "TUI_REFRESH_INTERVAL", "TUI_DEBUG_ACTIVE",
+}
+
+const psqlFile = `
+[log]
+level = "debug"
+
+[database]
+type = "psql"
+uri = "postgres://file"
+`
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileName  string // Config file passed as override, if set
+		file      string
+		userFile  bool // File is written to default location instead
+		env       map[string]string
+		overrides Overrides
+		wantType  string
+		wantURI   string
+		wantLevel string
+		err       string
+	}{
+		{
+			name:      "defaults",
+			wantType:  "sqlite",
+			wantURI:   DefaultDatabaseSqliteURI,
+			wantLevel: DefaultLoggerLevel,
+		},
+		{
+			name:      "file",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			wantType:  "psql",
+			wantURI:   "postgres://file",
+			wantLevel: "debug",
+		},
+		{
+			name:      "default file",
+			file:      psqlFile,
+			userFile:  true,
+			wantType:  "psql",
+			wantURI:   "postgres://file",
+			wantLevel: "debug",
+		},
+		{
+			name:      "yaml file",
+			fileName:  "config.yaml",
+			file:      "log:\n  level: warn\ndatabase:\n  type: psql\n",
+			wantType:  "psql",
+			wantURI:   DefaultDatabasePsqlURI,
+			wantLevel: "warn",
+		},
+		{
+			name:      "env over file",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			env:       map[string]string{"DATABASE_URI": "postgres://env", "LOG_LEVEL": "warn"},
+			wantType:  "psql",
+			wantURI:   "postgres://env",
+			wantLevel: "warn",
+		},
+		{
+			name:      "flag over env",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			env:       map[string]string{"DATABASE_URI": "postgres://env", "LOG_LEVEL": "warn"},
+			overrides: Overrides{DatabaseURI: "postgres://flag", LogLevel: "error"},
+			wantType:  "psql",
+			wantURI:   "postgres://flag",
+			wantLevel: "error",
+		},
+		{
+			name:      "env type switch drops file URI",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			env:       map[string]string{"DATABASE_TYPE": "sqlite"},
+			wantType:  "sqlite",
+			wantURI:   DefaultDatabaseSqliteURI,
+			wantLevel: "debug",
+		},
+		{
+			name:      "env type switch with URI",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			env:       map[string]string{"DATABASE_TYPE": "sqlite", "DATABASE_URI": "env.sqlite"},
+			wantType:  "sqlite",
+			wantURI:   "env.sqlite",
+			wantLevel: "debug",
+		},
+		{
+			name:      "env with the same type keeps file URI",
+			fileName:  "config.toml",
+			file:      psqlFile,
+			env:       map[string]string{"DATABASE_TYPE": "psql"},
+			wantType:  "psql",
+			wantURI:   "postgres://file",
+			wantLevel: "debug",
+		},
+		{
+			name:      "flag type switch drops env URI",
+			env:       map[string]string{"DATABASE_TYPE": "psql", "DATABASE_URI": "postgres://env"},
+			overrides: Overrides{DatabaseType: "sqlite"},
+			wantType:  "sqlite",
+			wantURI:   DefaultDatabaseSqliteURI,
+			wantLevel: DefaultLoggerLevel,
+		},
+		{
+			name:      "flag type switch with URI",
+			env:       map[string]string{"DATABASE_TYPE": "psql", "DATABASE_URI": "postgres://env"},
+			overrides: Overrides{DatabaseType: "sqlite", DatabaseURI: "flag.sqlite"},
+			wantType:  "sqlite",
+			wantURI:   "flag.sqlite",
+			wantLevel: DefaultLoggerLevel,
+		},
+		{
+			name:     "unknown file key",
+			fileName: "config.toml",
+			file:     "[database]\nurl = \"firn.sqlite\"\n",
+			err:      `unknown field "database.url"`,
+		},
+		{
+			name:      "missing file",
+			overrides: Overrides{ConfigFile: "missing.toml"},
+			err:       "failed to read config file",
+		},
+		{
+			name: "invalid env number",
+			env:  map[string]string{"SERVER_PORT": "http"},
+			err:  "invalid server port: http",
+		},
+		{
+			name:      "invalid flag value",
+			overrides: Overrides{LogLevel: "verbose"},
+			err:       "invalid log level: verbose",
+		},
+		{
+			name:      "unknown database type",
+			overrides: Overrides{DatabaseType: "mysql"},
+			err:       "invalid database type: mysql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			// Default config file is looked up in empty directory
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("HOME", dir)
+			for _, name := range configEnv {
+				t.Setenv(name, "")
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			overrides := tt.overrides
+			if tt.userFile {
+				configDir, err := os.UserConfigDir()
+				if err != nil {
+					t.Fatal(err)
+				}
+				path := filepath.Join(configDir, "firn", "config.toml")
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.fileName != "" {
+				overrides.ConfigFile = filepath.Join(dir, tt.fileName)
+				if err := os.WriteFile(overrides.ConfigFile, []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(overrides)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Load() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Database.Type != tt.wantType || cfg.Database.URI != tt.wantURI || cfg.Logger.Level != tt.wantLevel {
+				t.Errorf("Load() = type %q, URI %q, level %q, want %q, %q, %q",
+					cfg.Database.Type, cfg.Database.URI, cfg.Logger.Level, tt.wantType, tt.wantURI, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/kompotkot/firn/internal/types"
+)
+
+// Names of config file looked up in user config directory, the first
+// existing one is used
+var defaultFileNames = []string{"config.toml", "config.yaml", "config.yml"}
+
+// filePath returns path to config file from command line, FIRN_CONFIG
+// variable or default location in user config directory. Explicitly set
+// file is required to exist.
+func filePath(override string) (string, bool) {
+	if override != "" {
+		return override, true
+	}
+	if path := os.Getenv("FIRN_CONFIG"); path != "" {
+		return path, true
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+
+	for _, name := range defaultFileNames {
+		path := filepath.Join(configDir, "firn", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, false
+		}
+	}
+	return "", false
+}
+
+// loadFile decodes TOML or YAML config file, picked by its extension, on
+// top of cfg. Unknown keys are reported as errors to catch typos.
+func loadFile(path string, required bool, cfg *types.Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	default:
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("invalid config file %s: unknown field %q", path, undecoded[0].String())
+		}
+	}
+
+	return nil
+}
+
+// UsedFile returns path of config file read by Load with the same
+// override, empty if configuration comes from defaults and environment only
+func UsedFile(override string) string {
+	path, _ := filePath(override)
+	return path
+}
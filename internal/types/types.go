@@ -4,20 +4,37 @@ import "time"
 
 // Logger configuration
 type LoggerConfig struct {
-	Level  string
-	Format string
+	Level  string `toml:"level" yaml:"level"`
+	Format string `toml:"format" yaml:"format"`
 }
 
 // Database configuration
 type DatabaseConfig struct {
-	Type            string
-	URI             string
-	MaxConns        int
-	ConnMaxLifetime time.Duration
+	Type            string        `toml:"type" yaml:"type"`
+	URI             string        `toml:"uri" yaml:"uri"`
+	MaxConns        int           `toml:"max_open_conns" yaml:"max_open_conns"`
+	ConnMaxLifetime time.Duration `toml:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+}
+
+// REST API server configuration
+type ServerConfig struct {
+	Host string `toml:"host" yaml:"host"`
+	Port int    `toml:"port" yaml:"port"`
+}
+
+// Terminal User Interface configuration
+type TuiConfig struct {
+	Theme           string        `toml:"theme" yaml:"theme"`
+	ThemeFile       string        `toml:"theme_file" yaml:"theme_file"`
+	KeymapFile      string        `toml:"keymap_file" yaml:"keymap_file"`
+	RefreshInterval time.Duration `toml:"refresh_interval" yaml:"refresh_interval"`
+	Debug           bool          `toml:"debug" yaml:"debug"`
 }
 
 // Main configuration
 type Config struct {
-	Logger   LoggerConfig
-	Database DatabaseConfig
+	Logger   LoggerConfig   `toml:"log" yaml:"log"`
+	Database DatabaseConfig `toml:"database" yaml:"database"`
+	Server   ServerConfig   `toml:"server" yaml:"server"`
+	Tui      TuiConfig      `toml:"tui" yaml:"tui"`
 }
@@ -74,3 +74,7 @@ INSERT INTO tag_assignments (tag_id, entry_id) VALUES
     -- Entry 4: only one tag (personal)
     ('c3d4e5f6-7081-9012-cdef-123456789012', '4b00613a-400b-4075-9490-daef64aefade')
 ON CONFLICT DO NOTHING;
+
+-- Schema version checked by firn doctor, increase it with schema changes
+
+PRAGMA user_version = 1;
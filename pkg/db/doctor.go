@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CheckStatus is an outcome of a health check
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// CheckResult is an outcome of a single health check with details for user
+type CheckResult struct {
+	Name   string      `json:"name"`
+	Status CheckStatus `json:"status"`
+	Detail string      `json:"detail"`
+}
+
+// Diagnoser is implemented by databases which could check their schema
+// and consistency of stored data
+type Diagnoser interface {
+	// Diagnose runs health checks, failed queries are reported as failed checks
+	Diagnose(ctx context.Context) []CheckResult
+}
+
+// Tables of firn schema
+var ExpectedTables = []string{"journals", "entries", "tags", "tag_assignments"}
+
+// UniqueIndex is a primary key or unique constraint of firn schema
+type UniqueIndex struct {
+	Table   string
+	Columns []string
+}
+
+func (i UniqueIndex) String() string {
+	return fmt.Sprintf("%s(%s)", i.Table, strings.Join(i.Columns, ", "))
+}
+
+// Unique indexes of firn schema, queries rely on them for lookups and
+// conflict handling
+var ExpectedIndexes = []UniqueIndex{
+	{Table: "journals", Columns: []string{"id"}},
+	{Table: "entries", Columns: []string{"id"}},
+	{Table: "tags", Columns: []string{"id"}},
+	{Table: "tags", Columns: []string{"label"}},
+	{Table: "tag_assignments", Columns: []string{"tag_id", "entry_id"}},
+}
+
+// Queries counting rows referencing missing rows, they are run by all
+// backends as foreign keys could be disabled or added after the data
+var OrphanQueries = []struct {
+	Name  string
+	Query string
+}{
+	{
+		Name:  "Orphaned entries",
+		Query: "SELECT COUNT(*) FROM entries e LEFT JOIN journals j ON j.id = e.journal_id WHERE j.id IS NULL",
+	},
+	{
+		Name: "Orphaned tag assignments",
+		Query: `SELECT COUNT(*) FROM tag_assignments ta
+			LEFT JOIN entries e ON e.id = ta.entry_id
+			LEFT JOIN tags t ON t.id = ta.tag_id
+			WHERE e.id IS NULL OR t.id IS NULL`,
+	},
+}
+
+// CheckTables reports tables of firn schema missing from existing ones
+func CheckTables(existing []string) CheckResult {
+	var missing []string
+	for _, t := range ExpectedTables {
+		if !slices.Contains(existing, t) {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) > 0 {
+		return CheckResult{Name: "Tables", Status: CheckFail, Detail: "missing " + strings.Join(missing, ", ")}
+	}
+	return CheckResult{Name: "Tables", Status: CheckPass, Detail: strings.Join(ExpectedTables, ", ")}
+}
+
+// CheckIndexes reports unique indexes of firn schema missing from
+// existing ones, indexes are compared by table and ordered columns
+func CheckIndexes(existing []UniqueIndex) CheckResult {
+	var missing []string
+	for _, want := range ExpectedIndexes {
+		found := slices.ContainsFunc(existing, func(i UniqueIndex) bool {
+			return i.Table == want.Table && slices.Equal(i.Columns, want.Columns)
+		})
+		if !found {
+			missing = append(missing, want.String())
+		}
+	}
+	if len(missing) > 0 {
+		return CheckResult{Name: "Indexes", Status: CheckFail, Detail: "missing unique " + strings.Join(missing, ", ")}
+	}
+	return CheckResult{Name: "Indexes", Status: CheckPass, Detail: fmt.Sprintf("%d unique indexes", len(ExpectedIndexes))}
+}
+
+// CheckOrphans reports result of an orphan query
+func CheckOrphans(name string, count int, err error) CheckResult {
+	switch {
+	case err != nil:
+		return CheckResult{Name: name, Status: CheckFail, Detail: err.Error()}
+	case count > 0:
+		return CheckResult{Name: name, Status: CheckFail, Detail: fmt.Sprintf("%d found", count)}
+	}
+	return CheckResult{Name: name, Status: CheckPass, Detail: "none"}
+}
@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrJournalNotFound = errors.New("journal not found")
 	ErrEntryNotFound   = errors.New("entry not found")
+	ErrRestoreConflict = errors.New("record already exists")
 )
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+)
+
+// NewId generates a random UUID (version 4) used as identifier of new records
+func NewId() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// Namespace of identifiers derived by SourceId
+var sourceNamespace = [16]byte{0x6b, 0x1e, 0x0f, 0x52, 0x3c, 0x6a, 0x4e, 0x1d, 0x9a, 0x57, 0x2b, 0x84, 0x0d, 0xc3, 0x71, 0xe6}
+
+// SourceId derives a UUID (version 5) from source, such as path of an
+// imported file, so repeated imports of the same source get the same
+// identifier
+func SourceId(source string) string {
+	h := sha1.New()
+	h.Write(sourceNamespace[:])
+	h.Write([]byte(source))
+	b := h.Sum(nil)[:16]
+
+	b[6] = (b[6] & 0x0f) | 0x50 // Version 5
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/kompotkot/firn/pkg/kb"
 )
@@ -14,11 +15,24 @@ type Database interface {
 	// TestConnection tests the database connection with a timeout
 	TestConnection(ctx context.Context) error
 
-	// ListJournals lists all journals ordered by updated_at
-	ListJournals(ctx context.Context, orderByDesc bool, limit, offset int) ([]kb.Journal, error)
+	// ListJournals lists all journals ordered by the given field
+	ListJournals(ctx context.Context, orderBy OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error)
 
-	// ListEntries lists all entries for a journal
-	ListEntries(ctx context.Context, journalId string, orderByDesc bool, limit, offset int) ([]kb.Entry, error)
+	// ListEntries lists all entries for a journal ordered by the given field
+	ListEntries(ctx context.Context, journalId string, orderBy OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error)
+
+	// ListEntriesCreatedBetween lists entries of a journal created in [from, to) ordered by the given field
+	ListEntriesCreatedBetween(ctx context.Context, journalId string, from, to time.Time, orderBy OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error)
+
+	// ListEntryDates lists creation times of journal entries created in [from, to)
+	ListEntryDates(ctx context.Context, journalId string, from, to time.Time) ([]time.Time, error)
+
+	// GetJournalsMarker returns change marker of all journals
+	GetJournalsMarker(ctx context.Context) (ChangeMarker, error)
+
+	// GetEntriesMarker returns change marker of journal entries, only of
+	// entries created in [from, to) if to is not zero
+	GetEntriesMarker(ctx context.Context, journalId string, from, to time.Time) (ChangeMarker, error)
 
 	// GetJournalById retrieves a journal by its ID
 	GetJournalById(ctx context.Context, id string) (*kb.Journal, error)
@@ -26,18 +40,39 @@ type Database interface {
 	// CreateJournal creates a new journal with the given name
 	CreateJournal(ctx context.Context, name string) (*kb.Journal, error)
 
-	// DeleteJournal deletes a journal by its ID
+	// RenameJournal changes name of a journal
+	RenameJournal(ctx context.Context, id, name string) (*kb.Journal, error)
+
+	// SaveJournal creates a journal with ID and timestamps of the given one,
+	// or overwrites name and timestamps of an existing journal with this ID
+	SaveJournal(ctx context.Context, journal kb.Journal) (*kb.Journal, error)
+
+	// DeleteJournal deletes a journal with all its entries by its ID
 	DeleteJournal(ctx context.Context, id string) error
 
 	// GetEntryById retrieves an entry by journal ID and entry ID
 	GetEntryById(ctx context.Context, journalId, entryId string) (*kb.Entry, error)
 
+	// FindEntryById retrieves an entry by its ID in any journal
+	FindEntryById(ctx context.Context, entryId string) (*kb.Entry, error)
+
 	// CreateEntry creates a new entry in the specified journal
 	CreateEntry(ctx context.Context, journalId, title, content string) (*kb.Entry, error)
 
+	// SaveEntry creates an entry with ID and timestamps of the given one, or
+	// overwrites an existing entry with this ID, moving it to the journal of
+	// the given one
+	SaveEntry(ctx context.Context, entry kb.Entry) (*kb.Entry, error)
+
+	// UpdateEntry updates title and content of an entry
+	UpdateEntry(ctx context.Context, journalId, entryId, title, content string) (*kb.Entry, error)
+
 	// DeleteEntry deletes an entry by journal ID and entry ID
 	DeleteEntry(ctx context.Context, journalId, entryId string) error
 
+	// MoveEntry moves an entry to another journal keeping its tags
+	MoveEntry(ctx context.Context, journalId, entryId, targetJournalId string) (*kb.Entry, error)
+
 	// ListEntryTags lists all tags assigned to an entry
 	ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error)
 
@@ -50,12 +85,17 @@ type Database interface {
 	// ListTags lists all tags, optionally filtered by labels
 	ListTags(ctx context.Context, labels []string) ([]kb.Tag, error)
 
-	// CreateTags creates new tags with the given labels
+	// CreateTags creates new tags with the given labels, already existing tags are returned as is
 	CreateTags(ctx context.Context, labels []string) ([]kb.Tag, error)
 
 	// DeleteTags deletes tags by their IDs
 	DeleteTags(ctx context.Context, ids []string) error
 
+	// Restore loads snapshot in a single transaction keeping IDs of its
+	// records. Records which IDs are taken are handled by policy, tags are
+	// also matched by label and their assignments follow existing tags.
+	Restore(ctx context.Context, snapshot *Snapshot, policy ConflictPolicy) (RestoreStats, error)
+
 	// Close closes the database connection
 	Close() error
 }
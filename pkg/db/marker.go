@@ -0,0 +1,8 @@
+package db
+
+// ChangeMarker summarizes rows of a list cheaply, it differs between two
+// reads if rows were added, deleted or updated in between
+type ChangeMarker struct {
+	Count     int
+	UpdatedAt string // Latest update time of rows as stored by database
+}
@@ -0,0 +1,53 @@
+package db
+
+import "strings"
+
+// OrderBy is a field journals and entries lists are sorted by
+type OrderBy string
+
+const (
+	OrderByUpdated OrderBy = "updated"
+	OrderByCreated OrderBy = "created"
+	OrderByName    OrderBy = "name" // Name of journal or title of entry
+)
+
+// OrderByFields lists supported sort fields in the order they are cycled
+var OrderByFields = []OrderBy{OrderByUpdated, OrderByCreated, OrderByName}
+
+// Valid reports whether o is one of the supported sort fields
+func (o OrderBy) Valid() bool {
+	for _, f := range OrderByFields {
+		if o == f {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderClause builds ORDER BY clause for journals or entries table, nameColumn
+// is a column used for OrderByName. Rows are additionally ordered by id, so
+// pages are stable when sort values are equal. Unknown fields fall back to
+// updated_at.
+func OrderClause(o OrderBy, nameColumn string, desc bool) string {
+	column := "updated_at"
+	switch o {
+	case OrderByCreated:
+		column = "created_at"
+	case OrderByName:
+		column = nameColumn
+	}
+
+	direction := ""
+	if desc {
+		direction = " DESC"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(" ORDER BY ")
+	sb.WriteString(column)
+	sb.WriteString(direction)
+	sb.WriteString(", id")
+	sb.WriteString(direction)
+
+	return sb.String()
+}
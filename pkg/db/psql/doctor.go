@@ -0,0 +1,151 @@
+//go:build psql
+
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Diagnose checks schema version, tables and indexes, looks for invalid
+// indexes and constraints not validated against existing rows and for
+// orphaned rows
+func (p *PsqlDB) Diagnose(ctx context.Context) []db.CheckResult {
+	results := []db.CheckResult{p.checkSchemaVersion(ctx)}
+
+	tables, err := p.queryStrings(ctx, "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema()")
+	if err != nil {
+		results = append(results, db.CheckResult{Name: "Tables", Status: db.CheckFail, Detail: err.Error()})
+	} else {
+		results = append(results, db.CheckTables(tables))
+	}
+
+	indexes, err := p.uniqueIndexes(ctx)
+	if err != nil {
+		results = append(results, db.CheckResult{Name: "Indexes", Status: db.CheckFail, Detail: err.Error()})
+	} else {
+		results = append(results, db.CheckIndexes(indexes))
+	}
+
+	results = append(results, p.checkInvalidIndexes(ctx), p.checkConstraints(ctx))
+
+	for _, q := range db.OrphanQueries {
+		var count int
+		err := p.pool.QueryRow(ctx, q.Query).Scan(&count)
+		results = append(results, db.CheckOrphans(q.Name, count, err))
+	}
+
+	return results
+}
+
+// checkSchemaVersion reports version from schema_migrations table in
+// golang-migrate layout, if schema is versioned with it
+func (p *PsqlDB) checkSchemaVersion(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Schema version"}
+
+	var exists bool
+	if err := p.pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		result.Status, result.Detail = db.CheckFail, err.Error()
+		return result
+	}
+	if !exists {
+		result.Status, result.Detail = db.CheckWarn, "not recorded (no schema_migrations table), schema is checked by tables and indexes"
+		return result
+	}
+
+	var version int64
+	var dirty bool
+	err := p.pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	switch {
+	case err == pgx.ErrNoRows:
+		result.Status, result.Detail = db.CheckWarn, "schema_migrations table is empty"
+	case err != nil:
+		result.Status, result.Detail = db.CheckFail, err.Error()
+	case dirty:
+		result.Status, result.Detail = db.CheckFail, fmt.Sprintf("%d (dirty, last migration failed)", version)
+	default:
+		result.Status, result.Detail = db.CheckPass, fmt.Sprintf("%d", version)
+	}
+	return result
+}
+
+// uniqueIndexes lists primary keys and unique indexes of firn tables with
+// their columns in index order
+func (p *PsqlDB) uniqueIndexes(ctx context.Context) ([]db.UniqueIndex, error) {
+	query := `SELECT t.relname, array_agg(a.attname ORDER BY k.ord)
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE i.indisunique AND n.nspname = current_schema() AND t.relname = ANY($1)
+		GROUP BY i.indexrelid, t.relname`
+
+	rows, err := p.pool.Query(ctx, query, db.ExpectedTables)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (db.UniqueIndex, error) {
+		var index db.UniqueIndex
+		err := row.Scan(&index.Table, &index.Columns)
+		return index, err
+	})
+}
+
+// checkInvalidIndexes reports indexes left invalid by failed concurrent
+// builds, they are not used by queries and not enforce uniqueness
+func (p *PsqlDB) checkInvalidIndexes(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Index validity"}
+
+	query := `SELECT c.relname FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT i.indisvalid AND n.nspname = current_schema()`
+
+	invalid, err := p.queryStrings(ctx, query)
+	switch {
+	case err != nil:
+		result.Status, result.Detail = db.CheckFail, err.Error()
+	case len(invalid) > 0:
+		result.Status, result.Detail = db.CheckFail, "invalid "+strings.Join(invalid, ", ")
+	default:
+		result.Status, result.Detail = db.CheckPass, "all indexes valid"
+	}
+	return result
+}
+
+// checkConstraints reports foreign key and check constraints added with
+// NOT VALID and never validated, existing rows could violate them
+func (p *PsqlDB) checkConstraints(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Foreign keys"}
+
+	query := `SELECT c.conname FROM pg_constraint c
+		JOIN pg_namespace n ON n.oid = c.connamespace
+		WHERE NOT c.convalidated AND n.nspname = current_schema()`
+
+	unvalidated, err := p.queryStrings(ctx, query)
+	switch {
+	case err != nil:
+		result.Status, result.Detail = db.CheckFail, err.Error()
+	case len(unvalidated) > 0:
+		result.Status, result.Detail = db.CheckWarn, "not validated "+strings.Join(unvalidated, ", ")
+	default:
+		result.Status, result.Detail = db.CheckPass, "all constraints validated"
+	}
+	return result
+}
+
+// queryStrings returns first column of all rows returned by query
+func (p *PsqlDB) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -2,9 +2,11 @@ module github.com/kompotkot/firn/pkg/db/psql
 
 go 1.25.1
 
+replace github.com/kompotkot/firn => ../../..
+
 require (
 	github.com/jackc/pgx/v5 v5.7.6
-	github.com/kompotkot/firn v0.0.0-20251028111051-344401a7a521
+	github.com/kompotkot/firn v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -48,14 +48,12 @@ func (p *PsqlDB) Close() error {
 	return nil
 }
 
-// ListJournals lists all journals ordered by updated_at
-func (p *PsqlDB) ListJournals(ctx context.Context, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+// ListJournals lists all journals ordered by the given field
+func (p *PsqlDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
 	var sb strings.Builder
 
-	sb.WriteString("SELECT id, name, created_at, updated_at FROM journals ORDER BY updated_at")
-	if orderByDesc {
-		sb.WriteString(" DESC")
-	}
+	sb.WriteString("SELECT id, name, created_at, updated_at FROM journals")
+	sb.WriteString(db.OrderClause(orderBy, "name", orderByDesc))
 	sb.WriteString(" LIMIT $1 OFFSET $2")
 
 	query := sb.String()
@@ -73,14 +71,12 @@ func (p *PsqlDB) ListJournals(ctx context.Context, orderByDesc bool, limit, offs
 	return pgx.CollectRows(rows, pgx.RowToStructByName[kb.Journal])
 }
 
-// ListEntries lists all entries for a journal
-func (p *PsqlDB) ListEntries(ctx context.Context, journalId string, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+// ListEntries lists all entries for a journal ordered by the given field
+func (p *PsqlDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
 	var sb strings.Builder
 
-	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = $1 ORDER BY updated_at")
-	if orderByDesc {
-		sb.WriteString(" DESC")
-	}
+	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = $1")
+	sb.WriteString(db.OrderClause(orderBy, "title", orderByDesc))
 	sb.WriteString(" LIMIT $2 OFFSET $3")
 
 	query := sb.String()
@@ -98,22 +94,157 @@ func (p *PsqlDB) ListEntries(ctx context.Context, journalId string, orderByDesc
 	return pgx.CollectRows(rows, pgx.RowToStructByName[kb.Entry])
 }
 
+// ListEntriesCreatedBetween lists entries of a journal created in [from, to) ordered by the given field
+func (p *PsqlDB) ListEntriesCreatedBetween(ctx context.Context, journalId string, from, to time.Time, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	var sb strings.Builder
+
+	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = $1 AND created_at >= $2 AND created_at < $3")
+	sb.WriteString(db.OrderClause(orderBy, "title", orderByDesc))
+	sb.WriteString(" LIMIT $4 OFFSET $5")
+
+	query := sb.String()
+
+	if limit == 0 {
+		limit = db.ENTRY_LIST_DEFAULT_LIMIT
+	}
+
+	// Timestamps are stored without time zone in UTC
+	rows, err := p.pool.Query(ctx, query, journalId, from.UTC(), to.UTC(), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[kb.Entry])
+}
+
+// ListEntryDates lists creation times of journal entries created in [from, to)
+func (p *PsqlDB) ListEntryDates(ctx context.Context, journalId string, from, to time.Time) ([]time.Time, error) {
+	query := "SELECT created_at FROM entries WHERE journal_id = $1 AND created_at >= $2 AND created_at < $3 ORDER BY created_at"
+
+	rows, err := p.pool.Query(ctx, query, journalId, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowTo[time.Time])
+}
+
+// GetJournalsMarker returns change marker of all journals
+func (p *PsqlDB) GetJournalsMarker(ctx context.Context) (db.ChangeMarker, error) {
+	query := "SELECT count(*), coalesce(max(updated_at)::text, '') FROM journals"
+
+	var marker db.ChangeMarker
+	err := p.pool.QueryRow(ctx, query).Scan(&marker.Count, &marker.UpdatedAt)
+	return marker, err
+}
+
+// GetEntriesMarker returns change marker of journal entries, only of
+// entries created in [from, to) if to is not zero
+func (p *PsqlDB) GetEntriesMarker(ctx context.Context, journalId string, from, to time.Time) (db.ChangeMarker, error) {
+	query := "SELECT count(*), coalesce(max(updated_at)::text, '') FROM entries WHERE journal_id = $1"
+	args := []any{journalId}
+	if !to.IsZero() {
+		query += " AND created_at >= $2 AND created_at < $3"
+		args = append(args, from.UTC(), to.UTC())
+	}
+
+	var marker db.ChangeMarker
+	err := p.pool.QueryRow(ctx, query, args...).Scan(&marker.Count, &marker.UpdatedAt)
+	return marker, err
+}
+
 // GetJournalById retrieves a journal by its ID
 func (p *PsqlDB) GetJournalById(ctx context.Context, id string) (*kb.Journal, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	query := "SELECT id, name, created_at, updated_at FROM journals WHERE id = $1"
+
+	row := p.pool.QueryRow(ctx, query, id)
+
+	var journal kb.Journal
+	err := row.Scan(&journal.Id, &journal.Name, &journal.CreatedAt, &journal.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &journal, nil
 }
 
 // CreateJournal creates a new journal with the given name
 func (p *PsqlDB) CreateJournal(ctx context.Context, name string) (*kb.Journal, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	query := "INSERT INTO journals (id, name) VALUES ($1, $2) RETURNING id, name, created_at, updated_at"
+
+	row := p.pool.QueryRow(ctx, query, db.NewId(), name)
+
+	var journal kb.Journal
+	err := row.Scan(&journal.Id, &journal.Name, &journal.CreatedAt, &journal.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &journal, nil
 }
 
-// DeleteJournal deletes a journal by its ID
+// RenameJournal changes name of a journal
+func (p *PsqlDB) RenameJournal(ctx context.Context, id, name string) (*kb.Journal, error) {
+	query := "UPDATE journals SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING id, name, created_at, updated_at"
+
+	row := p.pool.QueryRow(ctx, query, name, id)
+
+	var journal kb.Journal
+	err := row.Scan(&journal.Id, &journal.Name, &journal.CreatedAt, &journal.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, db.ErrJournalNotFound
+		}
+		return nil, err
+	}
+
+	return &journal, nil
+}
+
+// SaveJournal creates a journal with the given ID or overwrites an existing one
+func (p *PsqlDB) SaveJournal(ctx context.Context, journal kb.Journal) (*kb.Journal, error) {
+	query := `INSERT INTO journals (id, name, created_at, updated_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, created_at = excluded.created_at, updated_at = excluded.updated_at
+		RETURNING id, name, created_at, updated_at`
+
+	row := p.pool.QueryRow(ctx, query, journal.Id, journal.Name, journal.CreatedAt, journal.UpdatedAt)
+
+	var saved kb.Journal
+	err := row.Scan(&saved.Id, &saved.Name, &saved.CreatedAt, &saved.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// DeleteJournal deletes a journal with all its entries by its ID
 func (p *PsqlDB) DeleteJournal(ctx context.Context, id string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Tag assignments of entries are removed by cascade
+	if _, err := tx.Exec(ctx, "DELETE FROM entries WHERE journal_id = $1", id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, "DELETE FROM journals WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrJournalNotFound
+	}
+
+	return tx.Commit(ctx)
 }
 
 // GetEntryById retrieves an entry by journal ID and entry ID
@@ -134,50 +265,238 @@ func (p *PsqlDB) GetEntryById(ctx context.Context, journalId, entryId string) (*
 	return &entry, nil
 }
 
+// FindEntryById retrieves an entry by its ID in any journal
+func (p *PsqlDB) FindEntryById(ctx context.Context, entryId string) (*kb.Entry, error) {
+	query := "SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE id = $1"
+
+	row := p.pool.QueryRow(ctx, query, entryId)
+
+	var entry kb.Entry
+	err := row.Scan(&entry.Id, &entry.JournalId, &entry.Title, &entry.Content, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
 // CreateEntry creates a new entry in the specified journal
 func (p *PsqlDB) CreateEntry(ctx context.Context, journalId, title, content string) (*kb.Entry, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	if err := p.checkJournalExists(ctx, journalId); err != nil {
+		return nil, err
+	}
+
+	query := "INSERT INTO entries (id, journal_id, title, content) VALUES ($1, $2, $3, $4) RETURNING id, journal_id, title, content, created_at, updated_at"
+
+	row := p.pool.QueryRow(ctx, query, db.NewId(), journalId, title, content)
+
+	var entry kb.Entry
+	err := row.Scan(&entry.Id, &entry.JournalId, &entry.Title, &entry.Content, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// SaveEntry creates an entry with the given ID or overwrites an existing one
+func (p *PsqlDB) SaveEntry(ctx context.Context, entry kb.Entry) (*kb.Entry, error) {
+	if err := p.checkJournalExists(ctx, entry.JournalId); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO entries (id, journal_id, title, content, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET journal_id = excluded.journal_id, title = excluded.title, content = excluded.content,
+			created_at = excluded.created_at, updated_at = excluded.updated_at
+		RETURNING id, journal_id, title, content, created_at, updated_at`
+
+	row := p.pool.QueryRow(ctx, query, entry.Id, entry.JournalId, entry.Title, entry.Content, entry.CreatedAt, entry.UpdatedAt)
+
+	var saved kb.Entry
+	err := row.Scan(&saved.Id, &saved.JournalId, &saved.Title, &saved.Content, &saved.CreatedAt, &saved.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// UpdateEntry updates title and content of an entry
+func (p *PsqlDB) UpdateEntry(ctx context.Context, journalId, entryId, title, content string) (*kb.Entry, error) {
+	query := "UPDATE entries SET title = $1, content = $2, updated_at = CURRENT_TIMESTAMP WHERE journal_id = $3 AND id = $4 RETURNING id, journal_id, title, content, created_at, updated_at"
+
+	row := p.pool.QueryRow(ctx, query, title, content, journalId, entryId)
+
+	var entry kb.Entry
+	err := row.Scan(&entry.Id, &entry.JournalId, &entry.Title, &entry.Content, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, db.ErrEntryNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
 }
 
 // DeleteEntry deletes an entry by journal ID and entry ID
 func (p *PsqlDB) DeleteEntry(ctx context.Context, journalId, entryId string) error {
-	// TODO(kompotkot): Implement
+	tag, err := p.pool.Exec(ctx, "DELETE FROM entries WHERE journal_id = $1 AND id = $2", journalId, entryId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return db.ErrEntryNotFound
+	}
+
+	return nil
+}
+
+// MoveEntry moves an entry to another journal keeping its tags
+func (p *PsqlDB) MoveEntry(ctx context.Context, journalId, entryId, targetJournalId string) (*kb.Entry, error) {
+	if err := p.checkJournalExists(ctx, targetJournalId); err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE entries SET journal_id = $1, updated_at = CURRENT_TIMESTAMP WHERE journal_id = $2 AND id = $3 RETURNING id, journal_id, title, content, created_at, updated_at"
+
+	row := p.pool.QueryRow(ctx, query, targetJournalId, journalId, entryId)
+
+	var entry kb.Entry
+	err := row.Scan(&entry.Id, &entry.JournalId, &entry.Title, &entry.Content, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, db.ErrEntryNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// checkJournalExists returns ErrJournalNotFound if there is no such journal
+func (p *PsqlDB) checkJournalExists(ctx context.Context, journalId string) error {
+	var exists int
+	err := p.pool.QueryRow(ctx, "SELECT 1 FROM journals WHERE id = $1", journalId).Scan(&exists)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.ErrJournalNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEntryExists returns ErrEntryNotFound if there is no such entry in journal
+func (p *PsqlDB) checkEntryExists(ctx context.Context, journalId, entryId string) error {
+	var exists int
+	err := p.pool.QueryRow(ctx, "SELECT 1 FROM entries WHERE journal_id = $1 AND id = $2", journalId, entryId).Scan(&exists)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.ErrEntryNotFound
+		}
+		return err
+	}
 	return nil
 }
 
 // ListEntryTags lists all tags assigned to an entry
 func (p *PsqlDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	query := `SELECT t.id, t.label FROM tags t
+		JOIN tag_assignments ta ON ta.tag_id = t.id
+		JOIN entries e ON e.id = ta.entry_id
+		WHERE e.journal_id = $1 AND e.id = $2
+		ORDER BY t.label`
+
+	rows, err := p.pool.Query(ctx, query, journalId, entryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[kb.Tag])
 }
 
 // AssignTagsToEntry assigns tags to an entry
 func (p *PsqlDB) AssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	if err := p.checkEntryExists(ctx, journalId, entryId); err != nil {
+		return err
+	}
+
+	query := "INSERT INTO tag_assignments (tag_id, entry_id) SELECT unnest($1::text[]), $2 ON CONFLICT DO NOTHING"
+
+	_, err := p.pool.Exec(ctx, query, tagIds, entryId)
+	return err
 }
 
 // DeAssignTagsToEntry removes tag assignments from an entry
 func (p *PsqlDB) DeAssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	if err := p.checkEntryExists(ctx, journalId, entryId); err != nil {
+		return err
+	}
+
+	query := "DELETE FROM tag_assignments WHERE entry_id = $1 AND tag_id = ANY($2)"
+
+	_, err := p.pool.Exec(ctx, query, entryId, tagIds)
+	return err
 }
 
 // ListTags lists all tags, optionally filtered by labels
 func (p *PsqlDB) ListTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	var rows pgx.Rows
+	var err error
+
+	if len(labels) > 0 {
+		rows, err = p.pool.Query(ctx, "SELECT id, label FROM tags WHERE label = ANY($1) ORDER BY label", labels)
+	} else {
+		rows, err = p.pool.Query(ctx, "SELECT id, label FROM tags ORDER BY label")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[kb.Tag])
 }
 
-// CreateTags creates new tags with the given labels
+// CreateTags creates new tags with the given labels, already existing tags are returned as is
 func (p *PsqlDB) CreateTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(labels))
+	for i := range labels {
+		ids[i] = db.NewId()
+	}
+
+	query := "INSERT INTO tags (id, label) SELECT unnest($1::text[]), unnest($2::text[]) ON CONFLICT (label) DO NOTHING"
+
+	if _, err := p.pool.Exec(ctx, query, ids, labels); err != nil {
+		return nil, err
+	}
+
+	return p.ListTags(ctx, labels)
 }
 
 // DeleteTags deletes tags by their IDs
 func (p *PsqlDB) DeleteTags(ctx context.Context, ids []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Tag assignments are removed by cascade
+	_, err := p.pool.Exec(ctx, "DELETE FROM tags WHERE id = ANY($1)", ids)
+	return err
 }
@@ -0,0 +1,187 @@
+//go:build psql
+
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// openTestDB opens a database in a new schema with tables and seed data of
+// migrations. It needs a server given by FIRN_TEST_PSQL_URI, the test is
+// skipped without it.
+func openTestDB(t *testing.T) *PsqlDB {
+	t.Helper()
+
+	uri := os.Getenv("FIRN_TEST_PSQL_URI")
+	if uri == "" {
+		t.Skip("FIRN_TEST_PSQL_URI is not set")
+	}
+
+	admin, err := NewPsqlDB(uri, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("firn_test_%d", time.Now().UnixNano())
+	if _, err := admin.pool.Exec(context.Background(), "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.pool.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") })
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	p, err := NewPsqlDB(u.String(), 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	migration, err := os.ReadFile("../../../migrations/psql/tmp.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.pool.Exec(context.Background(), string(migration)); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// restoreSnapshot returns snapshot with a journal, two entries and two
+// tags, label of the second one is taken by a tag of seed data
+func restoreSnapshot(title string) *db.Snapshot {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	return &db.Snapshot{
+		Journals: []kb.Journal{
+			{Id: "j1", Name: "Restored", CreatedAt: created, UpdatedAt: created},
+		},
+		Entries: []kb.Entry{
+			{Id: "e1", JournalId: "j1", Title: title, Content: "first", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+			{Id: "e2", JournalId: "j1", Title: title, Content: "second", CreatedAt: created, UpdatedAt: created},
+		},
+		Tags: []kb.Tag{
+			{Id: "t1", Label: "work"},
+			{Id: "t2", Label: "memo"},
+		},
+		TagAssignments: []kb.TagAssignment{
+			{TagId: "t1", EntryId: "e1"},
+			{TagId: "t2", EntryId: "e1"},
+		},
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  *db.Snapshot // Restored before the tested restore
+		policy    db.ConflictPolicy
+		want      db.RestoreStats
+		wantErr   error
+		wantTitle string
+	}{
+		{
+			name:   "empty database",
+			policy: db.ConflictFail,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Created: 1},
+				Entries:        db.RestoreCounts{Created: 2},
+				Tags:           db.RestoreCounts{Created: 1, Skipped: 1},
+				TagAssignments: db.RestoreCounts{Created: 2},
+			},
+			wantTitle: "New",
+		},
+		{
+			name:     "skip existing records",
+			existing: restoreSnapshot("Old"),
+			policy:   db.ConflictSkip,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Skipped: 1},
+				Entries:        db.RestoreCounts{Skipped: 2},
+				Tags:           db.RestoreCounts{Skipped: 2},
+				TagAssignments: db.RestoreCounts{Skipped: 2},
+			},
+			wantTitle: "Old",
+		},
+		{
+			name:     "overwrite existing records",
+			existing: restoreSnapshot("Old"),
+			policy:   db.ConflictOverwrite,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Overwritten: 1},
+				Entries:        db.RestoreCounts{Overwritten: 2},
+				Tags:           db.RestoreCounts{Overwritten: 1, Skipped: 1},
+				TagAssignments: db.RestoreCounts{Created: 2},
+			},
+			wantTitle: "New",
+		},
+		{
+			name:      "fail on existing records",
+			existing:  restoreSnapshot("Old"),
+			policy:    db.ConflictFail,
+			wantErr:   db.ErrRestoreConflict,
+			wantTitle: "Old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			p := openTestDB(t)
+
+			if tt.existing != nil {
+				if _, err := p.Restore(ctx, tt.existing, db.ConflictFail); err != nil {
+					t.Fatalf("Restore() of existing records error = %v", err)
+				}
+			}
+
+			snapshot := restoreSnapshot("New")
+			stats, err := p.Restore(ctx, snapshot, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Restore() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && stats != tt.want {
+				t.Errorf("Restore() stats = %+v, want %+v", stats, tt.want)
+			}
+
+			entry, err := p.FindEntryById(ctx, "e1")
+			if err != nil || entry == nil {
+				t.Fatalf("FindEntryById() = %v, %v", entry, err)
+			}
+			if entry.Title != tt.wantTitle {
+				t.Errorf("entry title = %q, want %q", entry.Title, tt.wantTitle)
+			}
+			want := snapshot.Entries[0]
+			if !entry.CreatedAt.Equal(want.CreatedAt) || !entry.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("entry timestamps = %v, %v, want %v, %v", entry.CreatedAt, entry.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+			}
+
+			// Tag with taken label is assigned by the label
+			tags, err := p.ListEntryTags(ctx, "j1", "e1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			labels := make(map[string]string)
+			for _, tag := range tags {
+				labels[tag.Label] = tag.Id
+			}
+			if len(tags) != 2 || labels["work"] != "t1" || labels["memo"] == "" || labels["memo"] == "t2" {
+				t.Errorf("entry tags = %+v, want work with its ID and memo with ID of seed data", tags)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"sort"
 )
 
 // DatabaseFactory handles database initialization
@@ -21,22 +22,18 @@ func RegisterDatabase(factory DatabaseFactory) {
 func CreateDatabase(dbType, uri string, maxConns int, connMaxLifetime int64) (Database, error) {
 	factory, exists := databaseFactories[dbType]
 	if !exists {
-		// Collect list of available types for error
-		available := make([]string, 0, len(databaseFactories))
-		for dbType := range databaseFactories {
-			available = append(available, dbType)
-		}
-		return nil, fmt.Errorf("unsupported database type: %s. Available types: %v", dbType, available)
+		return nil, fmt.Errorf("unsupported database type: %s. Available types: %v", dbType, GetAvailableDatabaseTypes())
 	}
 
 	return factory.Create(uri, maxConns, connMaxLifetime)
 }
 
-// GetAvailableDatabaseTypes returns a list of available database types
+// GetAvailableDatabaseTypes returns a sorted list of available database types
 func GetAvailableDatabaseTypes() []string {
 	types := make([]string, 0, len(databaseFactories))
 	for dbType := range databaseFactories {
 		types = append(types, dbType)
 	}
+	sort.Strings(types)
 	return types
 }
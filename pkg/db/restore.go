@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// ConflictPolicy decides what restore does with a record which ID is
+// already taken
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"      // Keep existing record
+	ConflictOverwrite ConflictPolicy = "overwrite" // Replace existing record
+	ConflictFail      ConflictPolicy = "fail"      // Abort restore
+)
+
+// ConflictPolicies lists supported conflict policies
+var ConflictPolicies = []ConflictPolicy{ConflictSkip, ConflictOverwrite, ConflictFail}
+
+// ParseConflictPolicy returns conflict policy by its name
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	for _, p := range ConflictPolicies {
+		if string(p) == value {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown conflict policy %q, expected skip, overwrite or fail", value)
+}
+
+// Snapshot is a content of database restored with IDs and timestamps
+type Snapshot struct {
+	Journals       []kb.Journal       `json:"journals"`
+	Entries        []kb.Entry         `json:"entries"`
+	Tags           []kb.Tag           `json:"tags"`
+	TagAssignments []kb.TagAssignment `json:"tag_assignments"`
+}
+
+// RestoreCounts counts records of one kind by restore outcome
+type RestoreCounts struct {
+	Created     int `json:"created"`
+	Overwritten int `json:"overwritten"`
+	Skipped     int `json:"skipped"`
+}
+
+// RestoreStats counts restored records by their kind
+type RestoreStats struct {
+	Journals       RestoreCounts `json:"journals"`
+	Entries        RestoreCounts `json:"entries"`
+	Tags           RestoreCounts `json:"tags"`
+	TagAssignments RestoreCounts `json:"tag_assignments"`
+}
+
+// Count adds outcome of a record to counts, created is false when the ID
+// was taken and outcome depends on policy
+func (c *RestoreCounts) Count(created bool, policy ConflictPolicy) {
+	switch {
+	case created:
+		c.Created++
+	case policy == ConflictOverwrite:
+		c.Overwritten++
+	default:
+		c.Skipped++
+	}
+}
@@ -0,0 +1,173 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+// Diagnose checks schema version, tables and indexes, runs SQLite
+// integrity and foreign key checks and looks for orphaned rows
+func (s *SqliteDB) Diagnose(ctx context.Context) []db.CheckResult {
+	results := []db.CheckResult{s.checkSchemaVersion(ctx)}
+
+	tables, err := s.queryStrings(ctx, "SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		results = append(results, db.CheckResult{Name: "Tables", Status: db.CheckFail, Detail: err.Error()})
+	} else {
+		results = append(results, db.CheckTables(tables))
+	}
+
+	indexes, err := s.uniqueIndexes(ctx)
+	if err != nil {
+		results = append(results, db.CheckResult{Name: "Indexes", Status: db.CheckFail, Detail: err.Error()})
+	} else {
+		results = append(results, db.CheckIndexes(indexes))
+	}
+
+	results = append(results, s.checkIntegrity(ctx), s.checkForeignKeys(ctx))
+
+	for _, q := range db.OrphanQueries {
+		var count int
+		err := s.db.QueryRowContext(ctx, q.Query).Scan(&count)
+		results = append(results, db.CheckOrphans(q.Name, count, err))
+	}
+
+	return results
+}
+
+// schemaVersion is user_version set by migrations/sqlite, schema of older
+// or newer version does not match queries of this build
+const schemaVersion = 1
+
+// checkSchemaVersion compares version stored in user_version pragma with
+// schemaVersion, it stays zero if schema was applied by hand
+func (s *SqliteDB) checkSchemaVersion(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Schema version"}
+
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		result.Status, result.Detail = db.CheckFail, err.Error()
+		return result
+	}
+
+	switch {
+	case version == 0:
+		result.Status, result.Detail = db.CheckWarn, fmt.Sprintf("not recorded (user_version is 0, expected %d), schema is checked by tables and indexes", schemaVersion)
+	case version < schemaVersion:
+		result.Status, result.Detail = db.CheckFail, fmt.Sprintf("%d is older than expected %d, apply newer migrations", version, schemaVersion)
+	case version > schemaVersion:
+		result.Status, result.Detail = db.CheckFail, fmt.Sprintf("%d is newer than expected %d, upgrade firn", version, schemaVersion)
+	default:
+		result.Status, result.Detail = db.CheckPass, fmt.Sprintf("%d", version)
+	}
+	return result
+}
+
+// uniqueIndexes lists primary keys and unique constraints of firn tables
+func (s *SqliteDB) uniqueIndexes(ctx context.Context) ([]db.UniqueIndex, error) {
+	var indexes []db.UniqueIndex
+	for _, table := range db.ExpectedTables {
+		rows, err := s.db.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_index_list('%s') WHERE \"unique\" = 1", table))
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			columns, err := s.queryStrings(ctx, "SELECT name FROM pragma_index_info(?) ORDER BY seqno", name)
+			if err != nil {
+				return nil, err
+			}
+			indexes = append(indexes, db.UniqueIndex{Table: table, Columns: columns})
+		}
+	}
+	return indexes, nil
+}
+
+// checkIntegrity runs integrity_check pragma, which returns single "ok"
+// row for a healthy database
+func (s *SqliteDB) checkIntegrity(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Integrity"}
+
+	problems, err := s.queryStrings(ctx, "PRAGMA integrity_check")
+	switch {
+	case err != nil:
+		result.Status, result.Detail = db.CheckFail, err.Error()
+	case len(problems) == 1 && problems[0] == "ok":
+		result.Status, result.Detail = db.CheckPass, "integrity_check ok"
+	default:
+		result.Status, result.Detail = db.CheckFail, strings.Join(problems, "; ")
+	}
+	return result
+}
+
+// checkForeignKeys runs foreign_key_check pragma and reports violations
+// grouped by table and referenced table
+func (s *SqliteDB) checkForeignKeys(ctx context.Context) db.CheckResult {
+	result := db.CheckResult{Name: "Foreign keys"}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT \"table\", parent, COUNT(*) FROM pragma_foreign_key_check GROUP BY \"table\", parent")
+	if err != nil {
+		result.Status, result.Detail = db.CheckFail, err.Error()
+		return result
+	}
+	defer rows.Close()
+
+	var violations []string
+	for rows.Next() {
+		var table, parent string
+		var count int
+		if err := rows.Scan(&table, &parent, &count); err != nil {
+			result.Status, result.Detail = db.CheckFail, err.Error()
+			return result
+		}
+		violations = append(violations, fmt.Sprintf("%d rows of %s reference missing %s", count, table, parent))
+	}
+	if err := rows.Err(); err != nil {
+		result.Status, result.Detail = db.CheckFail, err.Error()
+		return result
+	}
+
+	if len(violations) > 0 {
+		result.Status, result.Detail = db.CheckFail, strings.Join(violations, "; ")
+		return result
+	}
+	result.Status, result.Detail = db.CheckPass, "foreign_key_check ok"
+	return result
+}
+
+// queryStrings returns first column of all rows returned by query
+func (s *SqliteDB) queryStrings(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
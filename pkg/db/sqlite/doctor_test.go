@@ -0,0 +1,122 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+func TestDiagnose(t *testing.T) {
+	schema, err := os.ReadFile("../../../migrations/sqlite/tmp.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		schema func(string) string
+		setup  []string // Statements run after schema with foreign keys off
+		want   map[string]db.CheckStatus
+		detail map[string]string
+	}{
+		{
+			name: "healthy",
+			want: map[string]db.CheckStatus{
+				"Schema version":           db.CheckPass,
+				"Tables":                   db.CheckPass,
+				"Indexes":                  db.CheckPass,
+				"Integrity":                db.CheckPass,
+				"Foreign keys":             db.CheckPass,
+				"Orphaned entries":         db.CheckPass,
+				"Orphaned tag assignments": db.CheckPass,
+			},
+		},
+		{
+			name:   "unversioned",
+			setup:  []string{"PRAGMA user_version = 0"},
+			want:   map[string]db.CheckStatus{"Schema version": db.CheckWarn},
+			detail: map[string]string{"Schema version": "expected 1"},
+		},
+		{
+			name:   "newer version",
+			setup:  []string{"PRAGMA user_version = 2"},
+			want:   map[string]db.CheckStatus{"Schema version": db.CheckFail},
+			detail: map[string]string{"Schema version": "2 is newer than expected 1"},
+		},
+		{
+			name: "missing index",
+			schema: func(s string) string {
+				return strings.Replace(s, "label       TEXT NOT NULL UNIQUE", "label       TEXT NOT NULL", 1)
+			},
+			want:   map[string]db.CheckStatus{"Indexes": db.CheckFail, "Tables": db.CheckPass},
+			detail: map[string]string{"Indexes": "missing unique tags(label)"},
+		},
+		{
+			name:  "orphaned tag assignment",
+			setup: []string{"INSERT INTO tag_assignments (tag_id, entry_id) VALUES ('a1b2c3d4-5e6f-7890-abcd-ef1234567890', 'missing')"},
+			want: map[string]db.CheckStatus{
+				"Foreign keys":             db.CheckFail,
+				"Orphaned entries":         db.CheckPass,
+				"Orphaned tag assignments": db.CheckFail,
+			},
+			detail: map[string]string{
+				"Foreign keys":             "1 rows of tag_assignments reference missing entries",
+				"Orphaned tag assignments": "1 found",
+			},
+		},
+		{
+			name:  "missing table",
+			setup: []string{"DROP TABLE tag_assignments"},
+			want: map[string]db.CheckStatus{
+				"Tables":                   db.CheckFail,
+				"Orphaned tag assignments": db.CheckFail,
+			},
+			detail: map[string]string{
+				"Tables":                   "missing tag_assignments",
+				"Orphaned tag assignments": "no such table: tag_assignments",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, err := NewSqliteDB(filepath.Join(t.TempDir(), "firn.sqlite"), true, "NORMAL")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			migration := string(schema)
+			if tt.schema != nil {
+				migration = tt.schema(migration)
+			}
+			// Pool has a single connection, so pragma applies to all statements
+			statements := append([]string{migration, "PRAGMA foreign_keys = OFF"}, tt.setup...)
+			for _, statement := range statements {
+				if _, err := s.db.ExecContext(ctx, statement); err != nil {
+					t.Fatalf("%s: %v", statement, err)
+				}
+			}
+
+			results := s.Diagnose(ctx)
+			if len(results) != 7 {
+				t.Fatalf("Diagnose() returned %d checks, want 7: %+v", len(results), results)
+			}
+			for _, r := range results {
+				if want, ok := tt.want[r.Name]; ok && r.Status != want {
+					t.Errorf("%s = %s (%s), want %s", r.Name, r.Status, r.Detail, want)
+				}
+				if detail, ok := tt.detail[r.Name]; ok && !strings.Contains(r.Detail, detail) {
+					t.Errorf("%s detail = %q, want containing %q", r.Name, r.Detail, detail)
+				}
+			}
+		})
+	}
+}
@@ -2,15 +2,17 @@ module github.com/kompotkot/firn/pkg/db/sqlite
 
 go 1.25.1
 
+replace github.com/kompotkot/firn => ../../..
+
 require (
-	github.com/kompotkot/firn v0.0.0-20251028111051-344401a7a521
+	github.com/kompotkot/firn v0.0.0-00010101000000-000000000000
 	github.com/mattn/go-sqlite3 v1.14.32
 )
 
 require (
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
-	github.com/jackc/pgx/v5 v5.7.5 // indirect
+	github.com/jackc/pgx/v5 v5.7.6 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	golang.org/x/crypto v0.37.0 // indirect
 	golang.org/x/sync v0.13.0 // indirect
@@ -0,0 +1,128 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+func TestChangeMarkers(t *testing.T) {
+	ctx := context.Background()
+	s := openTestDB(t)
+
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	if _, err := s.SaveJournal(ctx, kb.Journal{Id: "j1", Name: "Markers", CreatedAt: created, UpdatedAt: created}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// Steps change database one after another
+	tests := []struct {
+		name        string
+		change      func() error
+		wantCount   int
+		wantDay     int  // Count of entries created on day
+		wantChanged bool // Latest update time of entries changed
+	}{
+		{
+			name:   "no entries",
+			change: func() error { return nil },
+		},
+		{
+			name: "entry created",
+			change: func() error {
+				_, err := s.SaveEntry(ctx, kb.Entry{Id: "e1", JournalId: "j1", Title: "One", CreatedAt: created, UpdatedAt: created})
+				return err
+			},
+			wantCount:   1,
+			wantDay:     1,
+			wantChanged: true,
+		},
+		{
+			name: "entry of another day",
+			change: func() error {
+				_, err := s.SaveEntry(ctx, kb.Entry{Id: "e2", JournalId: "j1", Title: "Two", CreatedAt: created.AddDate(0, 0, 1), UpdatedAt: created.AddDate(0, 0, 1)})
+				return err
+			},
+			wantCount:   2,
+			wantDay:     1,
+			wantChanged: true,
+		},
+		{
+			name:      "nothing changed",
+			change:    func() error { return nil },
+			wantCount: 2,
+			wantDay:   1,
+		},
+		{
+			name: "entry updated",
+			change: func() error {
+				_, err := s.UpdateEntry(ctx, "j1", "e1", "One", "Changed")
+				return err
+			},
+			wantCount:   2,
+			wantDay:     1,
+			wantChanged: true,
+		},
+		{
+			name:      "entry deleted",
+			change:    func() error { return s.DeleteEntry(ctx, "j1", "e2") },
+			wantCount: 1,
+			wantDay:   1,
+		},
+	}
+
+	previous, err := s.GetEntriesMarker(ctx, "j1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); err != nil {
+				t.Fatal(err)
+			}
+
+			marker, err := s.GetEntriesMarker(ctx, "j1", time.Time{}, time.Time{})
+			if err != nil {
+				t.Fatalf("GetEntriesMarker() error = %v", err)
+			}
+			if marker.Count != tt.wantCount || (marker.UpdatedAt != previous.UpdatedAt) != tt.wantChanged {
+				t.Errorf("GetEntriesMarker() = %+v after %+v, want count %d and changed update time %v", marker, previous, tt.wantCount, tt.wantChanged)
+			}
+			previous = marker
+
+			dayMarker, err := s.GetEntriesMarker(ctx, "j1", day, day.AddDate(0, 0, 1))
+			if err != nil {
+				t.Fatalf("GetEntriesMarker() of day error = %v", err)
+			}
+			if dayMarker.Count != tt.wantDay {
+				t.Errorf("GetEntriesMarker() of day count = %d, want %d", dayMarker.Count, tt.wantDay)
+			}
+		})
+	}
+
+	before, err := s.GetJournalsMarker(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RenameJournal(ctx, "j1", "Renamed"); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.GetJournalsMarker(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Count != before.Count || after.UpdatedAt == before.UpdatedAt {
+		t.Errorf("GetJournalsMarker() after rename = %+v, want count of %+v and changed update time", after, before)
+	}
+	if err := s.DeleteJournal(ctx, "j1"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := s.GetJournalsMarker(ctx); err != nil || deleted.Count != before.Count-1 {
+		t.Errorf("GetJournalsMarker() after delete = %+v, %v, want count %d", deleted, err, before.Count-1)
+	}
+}
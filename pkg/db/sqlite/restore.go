@@ -0,0 +1,137 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kompotkot/firn/pkg/db"
+)
+
+// Restore loads snapshot in a single transaction keeping IDs of records
+func (s *SqliteDB) Restore(ctx context.Context, snapshot *db.Snapshot, policy db.ConflictPolicy) (db.RestoreStats, error) {
+	var stats db.RestoreStats
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Rollback()
+
+	for _, j := range snapshot.Journals {
+		created, err := restoreRecord(ctx, tx, policy, "journal", j.Id,
+			"INSERT INTO journals (id, name, created_at, updated_at) VALUES (?, ?, ?, ?) ON CONFLICT (id) DO NOTHING",
+			[]any{j.Id, j.Name, timestamp(j.CreatedAt), timestamp(j.UpdatedAt)},
+			"UPDATE journals SET name = ?, created_at = ?, updated_at = ? WHERE id = ?",
+			[]any{j.Name, timestamp(j.CreatedAt), timestamp(j.UpdatedAt), j.Id},
+		)
+		if err != nil {
+			return stats, err
+		}
+		stats.Journals.Count(created, policy)
+	}
+
+	// Entries kept as they are keep their tags too
+	skippedEntries := make(map[string]bool)
+	for _, e := range snapshot.Entries {
+		created, err := restoreRecord(ctx, tx, policy, "entry", e.Id,
+			"INSERT INTO entries (id, journal_id, title, content, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING",
+			[]any{e.Id, e.JournalId, e.Title, e.Content, timestamp(e.CreatedAt), timestamp(e.UpdatedAt)},
+			"UPDATE entries SET journal_id = ?, title = ?, content = ?, created_at = ?, updated_at = ? WHERE id = ?",
+			[]any{e.JournalId, e.Title, e.Content, timestamp(e.CreatedAt), timestamp(e.UpdatedAt), e.Id},
+		)
+		if err != nil {
+			return stats, err
+		}
+		stats.Entries.Count(created, policy)
+
+		switch {
+		case created:
+		case policy == db.ConflictOverwrite:
+			if _, err := tx.ExecContext(ctx, "DELETE FROM tag_assignments WHERE entry_id = ?", e.Id); err != nil {
+				return stats, fmt.Errorf("failed to restore entry %s: %w", e.Id, err)
+			}
+		default:
+			skippedEntries[e.Id] = true
+		}
+	}
+
+	// Tags are matched by label, as labels are unique
+	tagIds := make(map[string]string)
+	for _, t := range snapshot.Tags {
+		var existingId string
+		err := tx.QueryRowContext(ctx, "SELECT id FROM tags WHERE label = ?", t.Label).Scan(&existingId)
+		switch {
+		case err == nil && existingId != t.Id:
+			tagIds[t.Id] = existingId
+			stats.Tags.Skipped++
+			continue
+		case err != nil && err != sql.ErrNoRows:
+			return stats, fmt.Errorf("failed to restore tag %s: %w", t.Id, err)
+		}
+
+		created, err := restoreRecord(ctx, tx, policy, "tag", t.Id,
+			"INSERT INTO tags (id, label) VALUES (?, ?) ON CONFLICT (id) DO NOTHING",
+			[]any{t.Id, t.Label},
+			"UPDATE tags SET label = ? WHERE id = ?",
+			[]any{t.Label, t.Id},
+		)
+		if err != nil {
+			return stats, err
+		}
+		stats.Tags.Count(created, policy)
+	}
+
+	for _, a := range snapshot.TagAssignments {
+		if skippedEntries[a.EntryId] {
+			stats.TagAssignments.Skipped++
+			continue
+		}
+
+		tagId := a.TagId
+		if id, ok := tagIds[tagId]; ok {
+			tagId = id
+		}
+
+		res, err := tx.ExecContext(ctx, "INSERT INTO tag_assignments (tag_id, entry_id) VALUES (?, ?) ON CONFLICT DO NOTHING", tagId, a.EntryId)
+		if err != nil {
+			return stats, fmt.Errorf("failed to restore tag assignment %s of entry %s: %w", a.TagId, a.EntryId, err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return stats, err
+		}
+		stats.TagAssignments.Count(affected > 0, db.ConflictSkip)
+	}
+
+	return stats, tx.Commit()
+}
+
+// restoreRecord inserts a record, if its ID is taken the record is
+// updated, kept or error is returned depending on policy. It reports
+// whether the record was inserted.
+func restoreRecord(ctx context.Context, tx *sql.Tx, policy db.ConflictPolicy, kind, id, insert string, insertArgs []any, update string, updateArgs []any) (bool, error) {
+	res, err := tx.ExecContext(ctx, insert, insertArgs...)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore %s %s: %w", kind, id, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected > 0 {
+		return true, nil
+	}
+
+	switch policy {
+	case db.ConflictFail:
+		return false, fmt.Errorf("%w: %s %s", db.ErrRestoreConflict, kind, id)
+	case db.ConflictOverwrite:
+		if _, err := tx.ExecContext(ctx, update, updateArgs...); err != nil {
+			return false, fmt.Errorf("failed to restore %s %s: %w", kind, id, err)
+		}
+	}
+	return false, nil
+}
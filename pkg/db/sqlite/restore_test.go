@@ -0,0 +1,159 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// openTestDB opens a database with schema and seed data of migrations
+func openTestDB(t *testing.T) *SqliteDB {
+	t.Helper()
+
+	s, err := NewSqliteDB(filepath.Join(t.TempDir(), "firn.sqlite"), true, "NORMAL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	schema, err := os.ReadFile("../../../migrations/sqlite/tmp.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// restoreSnapshot returns snapshot with a journal, two entries and two
+// tags, label of the second one is taken by a tag of seed data
+func restoreSnapshot(title string) *db.Snapshot {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	return &db.Snapshot{
+		Journals: []kb.Journal{
+			{Id: "j1", Name: "Restored", CreatedAt: created, UpdatedAt: created},
+		},
+		Entries: []kb.Entry{
+			{Id: "e1", JournalId: "j1", Title: title, Content: "first", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+			{Id: "e2", JournalId: "j1", Title: title, Content: "second", CreatedAt: created, UpdatedAt: created},
+		},
+		Tags: []kb.Tag{
+			{Id: "t1", Label: "work"},
+			{Id: "t2", Label: "memo"},
+		},
+		TagAssignments: []kb.TagAssignment{
+			{TagId: "t1", EntryId: "e1"},
+			{TagId: "t2", EntryId: "e1"},
+		},
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  *db.Snapshot // Restored before the tested restore
+		policy    db.ConflictPolicy
+		want      db.RestoreStats
+		wantErr   error
+		wantTitle string
+	}{
+		{
+			name:   "empty database",
+			policy: db.ConflictFail,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Created: 1},
+				Entries:        db.RestoreCounts{Created: 2},
+				Tags:           db.RestoreCounts{Created: 1, Skipped: 1},
+				TagAssignments: db.RestoreCounts{Created: 2},
+			},
+			wantTitle: "New",
+		},
+		{
+			name:     "skip existing records",
+			existing: restoreSnapshot("Old"),
+			policy:   db.ConflictSkip,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Skipped: 1},
+				Entries:        db.RestoreCounts{Skipped: 2},
+				Tags:           db.RestoreCounts{Skipped: 2},
+				TagAssignments: db.RestoreCounts{Skipped: 2},
+			},
+			wantTitle: "Old",
+		},
+		{
+			name:     "overwrite existing records",
+			existing: restoreSnapshot("Old"),
+			policy:   db.ConflictOverwrite,
+			want: db.RestoreStats{
+				Journals:       db.RestoreCounts{Overwritten: 1},
+				Entries:        db.RestoreCounts{Overwritten: 2},
+				Tags:           db.RestoreCounts{Overwritten: 1, Skipped: 1},
+				TagAssignments: db.RestoreCounts{Created: 2},
+			},
+			wantTitle: "New",
+		},
+		{
+			name:      "fail on existing records",
+			existing:  restoreSnapshot("Old"),
+			policy:    db.ConflictFail,
+			wantErr:   db.ErrRestoreConflict,
+			wantTitle: "Old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := openTestDB(t)
+
+			if tt.existing != nil {
+				if _, err := s.Restore(ctx, tt.existing, db.ConflictFail); err != nil {
+					t.Fatalf("Restore() of existing records error = %v", err)
+				}
+			}
+
+			snapshot := restoreSnapshot("New")
+			stats, err := s.Restore(ctx, snapshot, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Restore() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && stats != tt.want {
+				t.Errorf("Restore() stats = %+v, want %+v", stats, tt.want)
+			}
+
+			entry, err := s.FindEntryById(ctx, "e1")
+			if err != nil || entry == nil {
+				t.Fatalf("FindEntryById() = %v, %v", entry, err)
+			}
+			if entry.Title != tt.wantTitle {
+				t.Errorf("entry title = %q, want %q", entry.Title, tt.wantTitle)
+			}
+			want := snapshot.Entries[0]
+			if !entry.CreatedAt.Equal(want.CreatedAt) || !entry.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("entry timestamps = %v, %v, want %v, %v", entry.CreatedAt, entry.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+			}
+
+			// Tag with taken label is assigned by the label
+			tags, err := s.ListEntryTags(ctx, "j1", "e1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			labels := make(map[string]string)
+			for _, tag := range tags {
+				labels[tag.Label] = tag.Id
+			}
+			if len(tags) != 2 || labels["work"] != "t1" || labels["memo"] == "" || labels["memo"] == "t2" {
+				t.Errorf("entry tags = %+v, want work with its ID and memo with ID of seed data", tags)
+			}
+		})
+	}
+}
@@ -33,6 +33,10 @@ type SqliteDB struct {
 func NewSqliteDB(uri string, enableWal bool, syncPragma string) (*SqliteDB, error) {
 	params := url.Values{}
 
+	// Enable foreign key support for every connection of the pool, this is
+	// crucial for ON DELETE CASCADE and other FK actions to work
+	params.Add("_foreign_keys", "1")
+
 	if enableWal {
 		params.Add("_journal_mode", "WAL")
 	}
@@ -46,12 +50,10 @@ func NewSqliteDB(uri string, enableWal bool, syncPragma string) (*SqliteDB, erro
 	}
 
 	constructedUri := uri
-	if len(params) > 0 {
-		if strings.Contains(uri, "?") {
-			constructedUri += "&" + params.Encode()
-		} else {
-			constructedUri += "?" + params.Encode()
-		}
+	if strings.Contains(uri, "?") {
+		constructedUri += "&" + params.Encode()
+	} else {
+		constructedUri += "?" + params.Encode()
 	}
 
 	db, err := sql.Open("sqlite3", constructedUri)
@@ -64,14 +66,6 @@ func NewSqliteDB(uri string, enableWal bool, syncPragma string) (*SqliteDB, erro
 	db.SetMaxIdleConns(1)
 	db.SetConnMaxLifetime(time.Hour)
 
-	// Enable foreign key support for this connection.
-	// This is crucial for ON DELETE CASCADE and other FK actions to work.
-	_, err = db.Exec("PRAGMA foreign_keys = ON;")
-	if err != nil {
-		db.Close() // Close DB if we can't set the pragma
-		return nil, fmt.Errorf("failed to enable foreign key support for DSN '%s': %w", constructedUri, err)
-	}
-
 	return &SqliteDB{db: db}, nil
 }
 
@@ -91,14 +85,12 @@ func (s *SqliteDB) Close() error {
 	return nil
 }
 
-// ListJournals lists all journals ordered by updated_at
-func (s *SqliteDB) ListJournals(ctx context.Context, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+// ListJournals lists all journals ordered by the given field
+func (s *SqliteDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
 	var sb strings.Builder
 
-	sb.WriteString("SELECT id, name, created_at, updated_at FROM journals ORDER BY updated_at")
-	if orderByDesc {
-		sb.WriteString(" DESC")
-	}
+	sb.WriteString("SELECT id, name, created_at, updated_at FROM journals")
+	sb.WriteString(db.OrderClause(orderBy, "name", orderByDesc))
 	sb.WriteString(" LIMIT ? OFFSET ?")
 
 	query := sb.String()
@@ -129,14 +121,12 @@ func (s *SqliteDB) ListJournals(ctx context.Context, orderByDesc bool, limit, of
 	return journals, nil
 }
 
-// ListEntries lists all entries for a journal
-func (s *SqliteDB) ListEntries(ctx context.Context, journalId string, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+// ListEntries lists all entries for a journal ordered by the given field
+func (s *SqliteDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
 	var sb strings.Builder
 
-	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = ? ORDER BY updated_at")
-	if orderByDesc {
-		sb.WriteString(" DESC")
-	}
+	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = ?")
+	sb.WriteString(db.OrderClause(orderBy, "title", orderByDesc))
 	sb.WriteString(" LIMIT ? OFFSET ?")
 
 	query := sb.String()
@@ -167,22 +157,179 @@ func (s *SqliteDB) ListEntries(ctx context.Context, journalId string, orderByDes
 	return entries, nil
 }
 
+// ListEntriesCreatedBetween lists entries of a journal created in [from, to) ordered by the given field
+func (s *SqliteDB) ListEntriesCreatedBetween(ctx context.Context, journalId string, from, to time.Time, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	var sb strings.Builder
+
+	sb.WriteString("SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE journal_id = ? AND created_at >= ? AND created_at < ?")
+	sb.WriteString(db.OrderClause(orderBy, "title", orderByDesc))
+	sb.WriteString(" LIMIT ? OFFSET ?")
+
+	query := sb.String()
+
+	if limit == 0 {
+		limit = db.ENTRY_LIST_DEFAULT_LIMIT
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, journalId, timestamp(from), timestamp(to), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []kb.Entry
+	for rows.Next() {
+		var e kb.Entry
+		if err := rows.Scan(&e.Id, &e.JournalId, &e.Title, &e.Content, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ListEntryDates lists creation times of journal entries created in [from, to)
+func (s *SqliteDB) ListEntryDates(ctx context.Context, journalId string, from, to time.Time) ([]time.Time, error) {
+	query := "SELECT created_at FROM entries WHERE journal_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at"
+
+	rows, err := s.db.QueryContext(ctx, query, journalId, timestamp(from), timestamp(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var createdAt time.Time
+		if err := rows.Scan(&createdAt); err != nil {
+			return nil, err
+		}
+		dates = append(dates, createdAt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dates, nil
+}
+
+// GetJournalsMarker returns change marker of all journals
+func (s *SqliteDB) GetJournalsMarker(ctx context.Context) (db.ChangeMarker, error) {
+	query := "SELECT count(*), coalesce(max(updated_at), '') FROM journals"
+
+	var marker db.ChangeMarker
+	err := s.db.QueryRowContext(ctx, query).Scan(&marker.Count, &marker.UpdatedAt)
+	return marker, err
+}
+
+// GetEntriesMarker returns change marker of journal entries, only of
+// entries created in [from, to) if to is not zero
+func (s *SqliteDB) GetEntriesMarker(ctx context.Context, journalId string, from, to time.Time) (db.ChangeMarker, error) {
+	query := "SELECT count(*), coalesce(max(updated_at), '') FROM entries WHERE journal_id = ?"
+	args := []any{journalId}
+	if !to.IsZero() {
+		query += " AND created_at >= ? AND created_at < ?"
+		args = append(args, timestamp(from), timestamp(to))
+	}
+
+	var marker db.ChangeMarker
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&marker.Count, &marker.UpdatedAt)
+	return marker, err
+}
+
 // GetJournalById retrieves a journal by its ID
 func (s *SqliteDB) GetJournalById(ctx context.Context, id string) (*kb.Journal, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	query := "SELECT id, name, created_at, updated_at FROM journals WHERE id = ? LIMIT 1"
+
+	row := s.db.QueryRowContext(ctx, query, id)
+
+	var journal kb.Journal
+	err := row.Scan(&journal.Id, &journal.Name, &journal.CreatedAt, &journal.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &journal, nil
 }
 
 // CreateJournal creates a new journal with the given name
 func (s *SqliteDB) CreateJournal(ctx context.Context, name string) (*kb.Journal, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	id := db.NewId()
+	if _, err := s.db.ExecContext(ctx, "INSERT INTO journals (id, name) VALUES (?, ?)", id, name); err != nil {
+		return nil, err
+	}
+
+	return s.GetJournalById(ctx, id)
+}
+
+// RenameJournal changes name of a journal
+func (s *SqliteDB) RenameJournal(ctx context.Context, id, name string) (*kb.Journal, error) {
+	query := "UPDATE journals SET name = ?, updated_at = " + currentTimestamp + " WHERE id = ?"
+
+	res, err := s.db.ExecContext(ctx, query, name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, db.ErrJournalNotFound
+	}
+
+	return s.GetJournalById(ctx, id)
+}
+
+// SaveJournal creates a journal with the given ID or overwrites an existing one
+func (s *SqliteDB) SaveJournal(ctx context.Context, journal kb.Journal) (*kb.Journal, error) {
+	query := `INSERT INTO journals (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, created_at = excluded.created_at, updated_at = excluded.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, query, journal.Id, journal.Name, timestamp(journal.CreatedAt), timestamp(journal.UpdatedAt)); err != nil {
+		return nil, err
+	}
+
+	return s.GetJournalById(ctx, journal.Id)
 }
 
-// DeleteJournal deletes a journal by its ID
+// DeleteJournal deletes a journal with all its entries by its ID
 func (s *SqliteDB) DeleteJournal(ctx context.Context, id string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Tag assignments of entries are removed by cascade
+	if _, err := tx.ExecContext(ctx, "DELETE FROM entries WHERE journal_id = ?", id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM journals WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return db.ErrJournalNotFound
+	}
+
+	return tx.Commit()
 }
 
 // GetEntryById retrieves an entry by journal ID and entry ID
@@ -203,50 +350,305 @@ func (s *SqliteDB) GetEntryById(ctx context.Context, journalId, entryId string)
 	return &entry, nil
 }
 
+// FindEntryById retrieves an entry by its ID in any journal
+func (s *SqliteDB) FindEntryById(ctx context.Context, entryId string) (*kb.Entry, error) {
+	query := "SELECT id, journal_id, title, content, created_at, updated_at FROM entries WHERE id = ? LIMIT 1"
+
+	row := s.db.QueryRowContext(ctx, query, entryId)
+
+	var entry kb.Entry
+	err := row.Scan(&entry.Id, &entry.JournalId, &entry.Title, &entry.Content, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
 // CreateEntry creates a new entry in the specified journal
 func (s *SqliteDB) CreateEntry(ctx context.Context, journalId, title, content string) (*kb.Entry, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	if err := s.checkJournalExists(ctx, journalId); err != nil {
+		return nil, err
+	}
+
+	id := db.NewId()
+	query := "INSERT INTO entries (id, journal_id, title, content) VALUES (?, ?, ?, ?)"
+
+	if _, err := s.db.ExecContext(ctx, query, id, journalId, title, content); err != nil {
+		return nil, err
+	}
+
+	return s.GetEntryById(ctx, journalId, id)
+}
+
+// SaveEntry creates an entry with the given ID or overwrites an existing one
+func (s *SqliteDB) SaveEntry(ctx context.Context, entry kb.Entry) (*kb.Entry, error) {
+	if err := s.checkJournalExists(ctx, entry.JournalId); err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO entries (id, journal_id, title, content, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET journal_id = excluded.journal_id, title = excluded.title, content = excluded.content,
+			created_at = excluded.created_at, updated_at = excluded.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, query, entry.Id, entry.JournalId, entry.Title, entry.Content, timestamp(entry.CreatedAt), timestamp(entry.UpdatedAt)); err != nil {
+		return nil, err
+	}
+
+	return s.GetEntryById(ctx, entry.JournalId, entry.Id)
+}
+
+// UpdateEntry updates title and content of an entry
+func (s *SqliteDB) UpdateEntry(ctx context.Context, journalId, entryId, title, content string) (*kb.Entry, error) {
+	query := "UPDATE entries SET title = ?, content = ?, updated_at = " + currentTimestamp + " WHERE journal_id = ? AND id = ?"
+
+	res, err := s.db.ExecContext(ctx, query, title, content, journalId, entryId)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, db.ErrEntryNotFound
+	}
+
+	return s.GetEntryById(ctx, journalId, entryId)
 }
 
 // DeleteEntry deletes an entry by journal ID and entry ID
 func (s *SqliteDB) DeleteEntry(ctx context.Context, journalId, entryId string) error {
-	// TODO(kompotkot): Implement
+	res, err := s.db.ExecContext(ctx, "DELETE FROM entries WHERE journal_id = ? AND id = ?", journalId, entryId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return db.ErrEntryNotFound
+	}
+
 	return nil
 }
 
+// MoveEntry moves an entry to another journal keeping its tags
+func (s *SqliteDB) MoveEntry(ctx context.Context, journalId, entryId, targetJournalId string) (*kb.Entry, error) {
+	if err := s.checkJournalExists(ctx, targetJournalId); err != nil {
+		return nil, err
+	}
+
+	query := "UPDATE entries SET journal_id = ?, updated_at = " + currentTimestamp + " WHERE journal_id = ? AND id = ?"
+
+	res, err := s.db.ExecContext(ctx, query, targetJournalId, journalId, entryId)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, db.ErrEntryNotFound
+	}
+
+	return s.GetEntryById(ctx, targetJournalId, entryId)
+}
+
+// currentTimestamp is CURRENT_TIMESTAMP with milliseconds, updates made
+// within one second still change latest update time of change markers
+const currentTimestamp = "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+
+// timestamp formats time the same way as CURRENT_TIMESTAMP stores it,
+// so it could be compared with timestamp columns as text
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+// placeholders returns comma separated list of n query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// checkJournalExists returns ErrJournalNotFound if there is no such journal
+func (s *SqliteDB) checkJournalExists(ctx context.Context, journalId string) error {
+	var exists int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM journals WHERE id = ?", journalId).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrJournalNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEntryExists returns ErrEntryNotFound if there is no such entry in journal
+func (s *SqliteDB) checkEntryExists(ctx context.Context, journalId, entryId string) error {
+	var exists int
+	err := s.db.QueryRowContext(ctx, "SELECT 1 FROM entries WHERE journal_id = ? AND id = ?", journalId, entryId).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.ErrEntryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// scanTags collects tags from query rows
+func scanTags(rows *sql.Rows) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for rows.Next() {
+		var t kb.Tag
+		if err := rows.Scan(&t.Id, &t.Label); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
 // ListEntryTags lists all tags assigned to an entry
 func (s *SqliteDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	query := `SELECT t.id, t.label FROM tags t
+		JOIN tag_assignments ta ON ta.tag_id = t.id
+		JOIN entries e ON e.id = ta.entry_id
+		WHERE e.journal_id = ? AND e.id = ?
+		ORDER BY t.label`
+
+	rows, err := s.db.QueryContext(ctx, query, journalId, entryId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
 }
 
 // AssignTagsToEntry assigns tags to an entry
 func (s *SqliteDB) AssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	if err := s.checkEntryExists(ctx, journalId, entryId); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tagId := range tagIds {
+		_, err := tx.ExecContext(ctx, "INSERT INTO tag_assignments (tag_id, entry_id) VALUES (?, ?) ON CONFLICT DO NOTHING", tagId, entryId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // DeAssignTagsToEntry removes tag assignments from an entry
 func (s *SqliteDB) DeAssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	if err := s.checkEntryExists(ctx, journalId, entryId); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM tag_assignments WHERE entry_id = ? AND tag_id IN (%s)", placeholders(len(tagIds)))
+
+	args := make([]any, 0, len(tagIds)+1)
+	args = append(args, entryId)
+	for _, id := range tagIds {
+		args = append(args, id)
+	}
+
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
 }
 
 // ListTags lists all tags, optionally filtered by labels
 func (s *SqliteDB) ListTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	var sb strings.Builder
+
+	sb.WriteString("SELECT id, label FROM tags")
+	args := make([]any, 0, len(labels))
+	if len(labels) > 0 {
+		sb.WriteString(fmt.Sprintf(" WHERE label IN (%s)", placeholders(len(labels))))
+		for _, label := range labels {
+			args = append(args, label)
+		}
+	}
+	sb.WriteString(" ORDER BY label")
+
+	rows, err := s.db.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
 }
 
-// CreateTags creates new tags with the given labels
+// CreateTags creates new tags with the given labels, already existing tags are returned as is
 func (s *SqliteDB) CreateTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
-	// TODO(kompotkot): Implement
-	return nil, nil
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, label := range labels {
+		_, err := tx.ExecContext(ctx, "INSERT INTO tags (id, label) VALUES (?, ?) ON CONFLICT (label) DO NOTHING", db.NewId(), label)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.ListTags(ctx, labels)
 }
 
 // DeleteTags deletes tags by their IDs
 func (s *SqliteDB) DeleteTags(ctx context.Context, ids []string) error {
-	// TODO(kompotkot): Implement
-	return nil
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	// Tag assignments are removed by cascade
+	query := fmt.Sprintf("DELETE FROM tags WHERE id IN (%s)", placeholders(len(ids)))
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
 }
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"strings"
+)
+
+// NormalizeTagLabels trims labels, drops empty ones and duplicates, keeping order
+func NormalizeTagLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
+
+// SyncEntryTags makes labels the only tags assigned to an entry. Tags
+// which do not exist yet are created.
+func SyncEntryTags(ctx context.Context, database Database, journalId, entryId string, labels []string) error {
+	labels = NormalizeTagLabels(labels)
+
+	current, err := database.ListEntryTags(ctx, journalId, entryId)
+	if err != nil {
+		return err
+	}
+
+	var tagIds []string
+	if len(labels) > 0 {
+		tags, err := database.CreateTags(ctx, labels)
+		if err != nil {
+			return err
+		}
+		for _, t := range tags {
+			tagIds = append(tagIds, t.Id)
+		}
+	}
+
+	wanted := make(map[string]bool, len(tagIds))
+	for _, id := range tagIds {
+		wanted[id] = true
+	}
+
+	var removeIds []string
+	for _, t := range current {
+		if !wanted[t.Id] {
+			removeIds = append(removeIds, t.Id)
+		}
+	}
+
+	if len(removeIds) > 0 {
+		if err := database.DeAssignTagsToEntry(ctx, journalId, entryId, removeIds); err != nil {
+			return err
+		}
+	}
+
+	if len(tagIds) > 0 {
+		if err := database.AssignTagsToEntry(ctx, journalId, entryId, tagIds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
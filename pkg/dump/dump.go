@@ -0,0 +1,303 @@
+// Package dump writes and reads versioned JSON archives with all journals,
+// entries, tags and tag assignments of a database
+package dump
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+const (
+	// Name of archive format written in its header
+	FormatName = "firn"
+
+	// Version of archive layout, archives of newer versions are not read
+	Version = 1
+)
+
+// Format is a way archive is encoded
+type Format string
+
+const (
+	// FormatJSON is a single JSON document with a list per record kind
+	FormatJSON Format = "json"
+
+	// FormatJSONL is a header line followed by a line per record
+	FormatJSONL Format = "jsonl"
+)
+
+// Kinds of records in JSON Lines archive
+const (
+	recordJournal       = "journal"
+	recordEntry         = "entry"
+	recordTag           = "tag"
+	recordTagAssignment = "tag_assignment"
+)
+
+// Header identifies archive, it is the first line of JSON Lines archive
+// and the leading fields of JSON one
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// document is a JSON archive, header of JSON Lines archive is decoded into
+// it as well
+type document struct {
+	Header
+	db.Snapshot
+}
+
+// record is a line of JSON Lines archive
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Counts are numbers of records by their kind
+type Counts struct {
+	Journals       int
+	Entries        int
+	Tags           int
+	TagAssignments int
+}
+
+// writer encodes records in archive format, JSON document is written
+// section by section so records are not kept in memory
+type writer struct {
+	w       *bufio.Writer
+	format  Format
+	first   bool
+	section bool
+}
+
+func (wr *writer) header(h Header) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if wr.format == FormatJSON {
+		// Sections are added to the header object
+		_, err = wr.w.Write(data[:len(data)-1])
+		return err
+	}
+	_, err = fmt.Fprintf(wr.w, "%s\n", data)
+	return err
+}
+
+func (wr *writer) startSection(name string) error {
+	if wr.format != FormatJSON {
+		return nil
+	}
+	if wr.section {
+		wr.w.WriteString("\n]")
+	}
+	wr.section, wr.first = true, true
+	_, err := fmt.Fprintf(wr.w, ",%q:[", name)
+	return err
+}
+
+func (wr *writer) record(kind string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if wr.format == FormatJSON {
+		if !wr.first {
+			wr.w.WriteString(",")
+		}
+		wr.first = false
+		_, err = fmt.Fprintf(wr.w, "\n%s", data)
+		return err
+	}
+
+	line, err := json.Marshal(record{Type: kind, Data: data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(wr.w, "%s\n", line)
+	return err
+}
+
+func (wr *writer) close() error {
+	if wr.format == FormatJSON {
+		if wr.section {
+			wr.w.WriteString("\n]")
+		}
+		wr.w.WriteString("}\n")
+	}
+	return wr.w.Flush()
+}
+
+// eachJournal calls fn for all journals page by page in order of creation
+func eachJournal(ctx context.Context, database db.Database, fn func(kb.Journal) error) error {
+	for offset := 0; ; offset += db.JOURNAL_LIST_DEFAULT_LIMIT {
+		page, err := database.ListJournals(ctx, db.OrderByCreated, false, db.JOURNAL_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return err
+		}
+		for _, j := range page {
+			if err := fn(j); err != nil {
+				return err
+			}
+		}
+		if len(page) < db.JOURNAL_LIST_DEFAULT_LIMIT {
+			return nil
+		}
+	}
+}
+
+// eachEntry calls fn for all entries of journal page by page in order of
+// creation
+func eachEntry(ctx context.Context, database db.Database, journalId string, fn func(kb.Entry) error) error {
+	for offset := 0; ; offset += db.ENTRY_LIST_DEFAULT_LIMIT {
+		page, err := database.ListEntries(ctx, journalId, db.OrderByCreated, false, db.ENTRY_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return err
+		}
+		for _, e := range page {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if len(page) < db.ENTRY_LIST_DEFAULT_LIMIT {
+			return nil
+		}
+	}
+}
+
+// Write writes all records of database to w in order journals, entries,
+// tags and tag assignments. Journals and entries are streamed page by page,
+// only tag assignments are collected in memory.
+func Write(ctx context.Context, database db.Database, w io.Writer, format Format) (Counts, error) {
+	var counts Counts
+	wr := &writer{w: bufio.NewWriter(w), format: format}
+
+	if err := wr.header(Header{Format: FormatName, Version: Version, CreatedAt: time.Now().UTC()}); err != nil {
+		return counts, err
+	}
+
+	if err := wr.startSection("journals"); err != nil {
+		return counts, err
+	}
+	err := eachJournal(ctx, database, func(j kb.Journal) error {
+		counts.Journals++
+		return wr.record(recordJournal, j)
+	})
+	if err != nil {
+		return counts, err
+	}
+
+	if err := wr.startSection("entries"); err != nil {
+		return counts, err
+	}
+	var assignments []kb.TagAssignment
+	err = eachJournal(ctx, database, func(j kb.Journal) error {
+		return eachEntry(ctx, database, j.Id, func(e kb.Entry) error {
+			tags, err := database.ListEntryTags(ctx, j.Id, e.Id)
+			if err != nil {
+				return err
+			}
+			for _, t := range tags {
+				assignments = append(assignments, kb.TagAssignment{TagId: t.Id, EntryId: e.Id})
+			}
+			counts.Entries++
+			return wr.record(recordEntry, e)
+		})
+	})
+	if err != nil {
+		return counts, err
+	}
+
+	if err := wr.startSection("tags"); err != nil {
+		return counts, err
+	}
+	tags, err := database.ListTags(ctx, nil)
+	if err != nil {
+		return counts, err
+	}
+	for _, t := range tags {
+		if err := wr.record(recordTag, t); err != nil {
+			return counts, err
+		}
+	}
+	counts.Tags = len(tags)
+
+	if err := wr.startSection("tag_assignments"); err != nil {
+		return counts, err
+	}
+	for _, a := range assignments {
+		if err := wr.record(recordTagAssignment, a); err != nil {
+			return counts, err
+		}
+	}
+	counts.TagAssignments = len(assignments)
+
+	return counts, wr.close()
+}
+
+// Read decodes archive in JSON or JSON Lines format, the format is
+// recognized by content
+func Read(r io.Reader) (*Header, *db.Snapshot, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid dump: %w", err)
+	}
+	if doc.Format != FormatName {
+		return nil, nil, errors.New("invalid dump: not a firn archive")
+	}
+	if doc.Version < 1 || doc.Version > Version {
+		return nil, nil, fmt.Errorf("unsupported dump version %d, this firn reads versions up to %d", doc.Version, Version)
+	}
+
+	snapshot := doc.Snapshot
+	for line := 2; ; line++ {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, nil, fmt.Errorf("invalid dump record %d: %w", line, err)
+		}
+
+		var err error
+		switch rec.Type {
+		case recordJournal:
+			snapshot.Journals, err = appendRecord(snapshot.Journals, rec.Data)
+		case recordEntry:
+			snapshot.Entries, err = appendRecord(snapshot.Entries, rec.Data)
+		case recordTag:
+			snapshot.Tags, err = appendRecord(snapshot.Tags, rec.Data)
+		case recordTagAssignment:
+			snapshot.TagAssignments, err = appendRecord(snapshot.TagAssignments, rec.Data)
+		default:
+			err = fmt.Errorf("unknown type %q", rec.Type)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid dump record %d: %w", line, err)
+		}
+	}
+
+	return &doc.Header, &snapshot, nil
+}
+
+// appendRecord decodes record data and appends it to records
+func appendRecord[T any](records []T, data json.RawMessage) ([]T, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return records, err
+	}
+	return append(records, v), nil
+}
@@ -0,0 +1,163 @@
+package dump
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// snapshotDB serves records of snapshot, only methods used by Write are
+// implemented
+type snapshotDB struct {
+	db.Database
+	snapshot db.Snapshot
+}
+
+func (s *snapshotDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+	return page(s.snapshot.Journals, limit, offset), nil
+}
+
+func (s *snapshotDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	var entries []kb.Entry
+	for _, e := range s.snapshot.Entries {
+		if e.JournalId == journalId {
+			entries = append(entries, e)
+		}
+	}
+	return page(entries, limit, offset), nil
+}
+
+func (s *snapshotDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for _, a := range s.snapshot.TagAssignments {
+		if a.EntryId != entryId {
+			continue
+		}
+		for _, t := range s.snapshot.Tags {
+			if t.Id == a.TagId {
+				tags = append(tags, t)
+			}
+		}
+	}
+	return tags, nil
+}
+
+func (s *snapshotDB) ListTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
+	return s.snapshot.Tags, nil
+}
+
+func page[T any](records []T, limit, offset int) []T {
+	if offset >= len(records) {
+		return nil
+	}
+	return records[offset:min(offset+limit, len(records))]
+}
+
+func testSnapshot(journals, entriesPerJournal int) db.Snapshot {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	snapshot := db.Snapshot{
+		Tags: []kb.Tag{{Id: "t1", Label: "work"}, {Id: "t2", Label: "ideas"}},
+	}
+	for j := range journals {
+		journal := kb.Journal{Id: db.SourceId(fmt.Sprintf("journal:%d", j)), Name: fmt.Sprintf("Journal %d", j), CreatedAt: created, UpdatedAt: created.Add(time.Hour)}
+		snapshot.Journals = append(snapshot.Journals, journal)
+		for e := range entriesPerJournal {
+			entry := kb.Entry{
+				Id:        db.SourceId(fmt.Sprintf("entry:%d:%d", j, e)),
+				JournalId: journal.Id,
+				Title:     "Entry \"quoted\"",
+				Content:   "line\nwith unicode ✓",
+				CreatedAt: created.Add(time.Duration(e) * time.Minute),
+				UpdatedAt: created.Add(time.Duration(e) * time.Minute),
+			}
+			snapshot.Entries = append(snapshot.Entries, entry)
+			if e%2 == 0 {
+				snapshot.TagAssignments = append(snapshot.TagAssignments, kb.TagAssignment{TagId: "t1", EntryId: entry.Id})
+			}
+		}
+	}
+	return snapshot
+}
+
+func TestWriteRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		snapshot db.Snapshot
+	}{
+		{"json empty", FormatJSON, db.Snapshot{}},
+		{"jsonl empty", FormatJSONL, db.Snapshot{}},
+		{"json", FormatJSON, testSnapshot(2, 3)},
+		{"jsonl", FormatJSONL, testSnapshot(2, 3)},
+		{"json several pages", FormatJSON, testSnapshot(1, db.ENTRY_LIST_DEFAULT_LIMIT+5)},
+		{"jsonl several pages", FormatJSONL, testSnapshot(1, db.ENTRY_LIST_DEFAULT_LIMIT+5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			counts, err := Write(context.Background(), &snapshotDB{snapshot: tt.snapshot}, &buf, tt.format)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			want := Counts{len(tt.snapshot.Journals), len(tt.snapshot.Entries), len(tt.snapshot.Tags), len(tt.snapshot.TagAssignments)}
+			if counts != want {
+				t.Errorf("Write() counts = %+v, want %+v", counts, want)
+			}
+
+			header, snapshot, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read() error = %v\n%s", err, buf.String())
+			}
+			if header.Format != FormatName || header.Version != Version {
+				t.Errorf("Read() header = %+v", header)
+			}
+			if !equalSnapshots(*snapshot, tt.snapshot) {
+				t.Errorf("Read() snapshot = %+v, want %+v", *snapshot, tt.snapshot)
+			}
+		})
+	}
+}
+
+// equalSnapshots compares snapshots treating nil and empty lists as equal
+func equalSnapshots(a, b db.Snapshot) bool {
+	return equalRecords(a.Journals, b.Journals) && equalRecords(a.Entries, b.Entries) &&
+		equalRecords(a.Tags, b.Tags) && equalRecords(a.TagAssignments, b.TagAssignments)
+}
+
+func equalRecords[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || reflect.DeepEqual(a, b))
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		err     string
+	}{
+		{"empty", "", "invalid dump"},
+		{"not json", "journal: x", "invalid dump"},
+		{"other format", `{"format":"other","version":1}`, "not a firn archive"},
+		{"newer version", `{"format":"firn","version":2}`, "unsupported dump version 2"},
+		{"zero version", `{"format":"firn","version":0}`, "unsupported dump version 0"},
+		{"unknown record", "{\"format\":\"firn\",\"version\":1}\n{\"type\":\"note\",\"data\":{}}\n", `invalid dump record 2: unknown type "note"`},
+		{"invalid record", "{\"format\":\"firn\",\"version\":1}\n{\"type\":\"entry\",\"data\":{\"title\":1}}\n", "invalid dump record 2"},
+		{"truncated", "{\"format\":\"firn\",\"version\":1}\n{\"type\":\"entry\"", "invalid dump record 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(strings.NewReader(tt.archive))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Read() error = %v, want containing %q", err, tt.err)
+			}
+		})
+	}
+}
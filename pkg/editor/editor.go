@@ -0,0 +1,27 @@
+// Package editor launches external editor chosen by user
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Editor used when neither VISUAL nor EDITOR is set
+const DefaultEditor = "vi"
+
+// Command builds command to open path in user editor from VISUAL or EDITOR
+// variables, which could contain arguments like "code --wait"
+func Command(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{DefaultEditor}
+	}
+
+	return exec.Command(args[0], append(args[1:], path)...)
+}
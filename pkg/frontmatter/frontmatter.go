@@ -0,0 +1,71 @@
+// Package frontmatter reads and writes Markdown documents with YAML front matter
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+// Split separates YAML front matter from document body. If document
+// does not start with front matter, header is nil and body is the
+// whole document.
+func Split(data []byte) (header []byte, body []byte) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, delimiter+"\n") {
+		return nil, []byte(text)
+	}
+
+	rest := text[len(delimiter)+1:]
+	offset := 0
+	for _, line := range strings.SplitAfter(rest, "\n") {
+		if strings.TrimSuffix(line, "\n") == delimiter {
+			return append([]byte{}, rest[:offset]...), []byte(rest[offset+len(line):])
+		}
+		offset += len(line)
+	}
+
+	// Front matter is not closed, treat everything as body
+	return nil, []byte(text)
+}
+
+// Parse decodes front matter into v and returns document body without the
+// blank line separating it from front matter and without its final line
+// break, so body written by Format is returned unchanged
+func Parse(data []byte, v any) (string, error) {
+	header, body := Split(data)
+	if len(bytes.TrimSpace(header)) > 0 {
+		if err := yaml.Unmarshal(header, v); err != nil {
+			return "", fmt.Errorf("failed to parse front matter: %w", err)
+		}
+	}
+
+	text := string(body)
+	if header != nil {
+		text = strings.TrimPrefix(text, "\n")
+	}
+	return strings.TrimSuffix(text, "\n"), nil
+}
+
+// Format encodes v as YAML front matter followed by a blank line and
+// document body, a line break is always added after non-empty body
+func Format(v any, body string) ([]byte, error) {
+	header, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	buf.Write(header)
+	buf.WriteString(delimiter + "\n\n")
+	if body != "" {
+		buf.WriteString(body + "\n")
+	}
+
+	return buf.Bytes(), nil
+}
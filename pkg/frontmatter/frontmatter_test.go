@@ -0,0 +1,70 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+type testHeader struct {
+	Title string `yaml:"title"`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		title string
+		body  string
+		err   string
+	}{
+		{name: "empty", data: "", body: ""},
+		{name: "no front matter", data: "\nText\n", body: "\nText"},
+		{name: "header only", data: "---\ntitle: T\n---\n", title: "T", body: ""},
+		{name: "separator line", data: "---\ntitle: T\n---\n\nText\n", title: "T", body: "Text"},
+		{name: "no separator line", data: "---\ntitle: T\n---\nText", title: "T", body: "Text"},
+		{name: "blank lines kept", data: "---\ntitle: T\n---\n\n\nText\n\n", title: "T", body: "\nText\n"},
+		{name: "empty header", data: "---\n---\nText\n", body: "Text"},
+		{name: "windows line breaks", data: "---\r\ntitle: T\r\n---\r\n\r\nText\r\n", title: "T", body: "Text"},
+		{name: "unclosed front matter", data: "---\ntitle: T\nText\n", body: "---\ntitle: T\nText"},
+		{name: "invalid header", data: "---\ntitle: [\n---\n", err: "failed to parse front matter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header testHeader
+			body, err := Parse([]byte(tt.data), &header)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Parse() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if header.Title != tt.title || body != tt.body {
+				t.Errorf("Parse() = %q, %q, want %q, %q", header.Title, body, tt.title, tt.body)
+			}
+		})
+	}
+}
+
+func TestFormatParse(t *testing.T) {
+	bodies := []string{"", "Text", "Text\n", "\n", "\n\nText\n\n", "Line\nLine", "---\nNot a header\n---"}
+
+	for _, body := range bodies {
+		data, err := Format(testHeader{Title: "T"}, body)
+		if err != nil {
+			t.Fatalf("Format(%q) error = %v", body, err)
+		}
+
+		var header testHeader
+		got, err := Parse(data, &header)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", data, err)
+		}
+		if got != body || header.Title != "T" {
+			t.Errorf("Parse(Format(%q)) = %q, %q", body, header.Title, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+func init() {
+	Register(&dayOneImporter{})
+}
+
+// dayOneImporter reads Day One JSON exports, a zip archive or a directory
+// extracted from it with a JSON file per journal
+type dayOneImporter struct{}
+
+func (i *dayOneImporter) GetName() string {
+	return "dayone"
+}
+
+func (i *dayOneImporter) GetDescription() string {
+	return "Day One JSON export, zip archive, extracted directory or JSON file"
+}
+
+// dayOneExport is a JSON file of Day One export with entries of a journal
+type dayOneExport struct {
+	Entries []struct {
+		UUID         string    `json:"uuid"`
+		CreationDate time.Time `json:"creationDate"`
+		ModifiedDate time.Time `json:"modifiedDate"`
+		Text         string    `json:"text"`
+		Tags         []string  `json:"tags"`
+	} `json:"entries"`
+}
+
+// Day One escapes Markdown punctuation typed as plain text
+var dayOneEscapePattern = regexp.MustCompile(`\\([!-/:-@\[-` + "`" + `{-~])`)
+
+func (i *dayOneImporter) Read(p string) ([]Journal, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var journals []Journal
+	add := func(name string, r io.Reader) error {
+		journal, err := readDayOneJournal(name, r)
+		if err != nil {
+			return err
+		}
+		journals = append(journals, *journal)
+		return nil
+	}
+
+	switch {
+	case info.IsDir():
+		err = filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !isDayOneJournal(file) {
+				return err
+			}
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return add(file, f)
+		})
+	case strings.EqualFold(filepath.Ext(p), ".zip"):
+		err = readDayOneZip(p, add)
+	default:
+		var f *os.File
+		if f, err = os.Open(p); err == nil {
+			defer f.Close()
+			err = add(p, f)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(journals) == 0 {
+		return nil, fmt.Errorf("no Day One journals found in %s", p)
+	}
+	return journals, nil
+}
+
+// readDayOneZip calls add for every journal file in zip archive
+func readDayOneZip(p string, add func(name string, r io.Reader) error) error {
+	archive, err := zip.OpenReader(p)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isDayOneJournal(file.Name) {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = add(file.Name, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDayOneJournal reports whether file in export is a journal, metadata of
+// macOS archives is skipped
+func isDayOneJournal(name string) bool {
+	name = filepath.ToSlash(name)
+	return strings.EqualFold(path.Ext(name), ".json") &&
+		!strings.HasPrefix(path.Base(name), ".") && !strings.Contains(name, "__MACOSX/")
+}
+
+// readDayOneJournal decodes journal file, journal is named by the file
+func readDayOneJournal(name string, r io.Reader) (*Journal, error) {
+	var export dayOneExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid Day One journal %s: %w", name, err)
+	}
+
+	base := path.Base(filepath.ToSlash(name))
+	journal := &Journal{Name: strings.TrimSuffix(base, path.Ext(base))}
+	for _, e := range export.Entries {
+		title, content := kb.SplitTitle(dayOneEscapePattern.ReplaceAllString(e.Text, "$1"))
+		if title == "" {
+			title = e.CreationDate.Local().Format("2006-01-02 15:04")
+		}
+
+		updatedAt := e.ModifiedDate
+		if updatedAt.IsZero() {
+			updatedAt = e.CreationDate
+		}
+
+		key := e.UUID
+		if key == "" {
+			key = e.CreationDate.UTC().Format(time.RFC3339) + "|" + title
+		}
+
+		journal.Entries = append(journal.Entries, Entry{
+			Key:       key,
+			Title:     title,
+			Content:   content,
+			Tags:      e.Tags,
+			CreatedAt: e.CreationDate,
+			UpdatedAt: updatedAt,
+		})
+	}
+	return journal, nil
+}
@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"archive/zip"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Files of Day One export by their names, metadata files are not journals
+var dayOneFiles = map[string]string{
+	"Journal.json": `{"metadata": {"version": "1.0"}, "entries": [
+		{"uuid": "A1", "creationDate": "2024-01-02T10:00:00Z", "modifiedDate": "2024-01-03T11:00:00Z",
+			"text": "# First day\n\nIt was cold\\. Really\\!", "tags": ["winter", "walk"]},
+		{"uuid": "A2", "creationDate": "2024-01-04T09:00:00Z", "text": "Only a title"}
+	]}`,
+	"Work Notes.json": `{"entries": [
+		{"creationDate": "2024-02-01T08:30:00Z", "modifiedDate": "2024-02-01T08:30:00Z", "text": ""}
+	]}`,
+	"photos/0b1c.jpeg":           "not a journal",
+	"__MACOSX/._Journal.json":    "not a journal",
+	".hidden.json":               "not a journal",
+	"__MACOSX/photos/._0b1c.jpg": "not a journal",
+}
+
+// Journals read from dayOneFiles
+var dayOneJournals = []Journal{
+	{
+		Name: "Journal",
+		Entries: []Entry{
+			{
+				Key:       "A1",
+				Title:     "First day",
+				Content:   "It was cold. Really!",
+				Tags:      []string{"winter", "walk"},
+				CreatedAt: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC),
+			},
+			{
+				Key:       "A2",
+				Title:     "Only a title",
+				Content:   "",
+				CreatedAt: time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC),
+			},
+		},
+	},
+	{
+		Name: "Work Notes",
+		Entries: []Entry{
+			{
+				// Entry without UUID and text is keyed and titled by its date
+				Key:       "2024-02-01T08:30:00Z|" + time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC).Local().Format("2006-01-02 15:04"),
+				Title:     time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC).Local().Format("2006-01-02 15:04"),
+				CreatedAt: time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC),
+			},
+		},
+	},
+}
+
+// writeDayOneDir writes files to dir under prefix
+func writeDayOneDir(t *testing.T, dir, prefix string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, prefix, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeDayOneZip writes files to zip archive in dir in order of their names
+// and returns its path
+func writeDayOneZip(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(dir, "export.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDayOneRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, dir string) string // Returns path to read
+		want    []Journal
+		err     string
+	}{
+		{
+			name: "zip archive",
+			prepare: func(t *testing.T, dir string) string {
+				return writeDayOneZip(t, dir, dayOneFiles)
+			},
+			want: dayOneJournals,
+		},
+		{
+			name: "extracted directory",
+			prepare: func(t *testing.T, dir string) string {
+				writeDayOneDir(t, dir, "export", dayOneFiles)
+				return filepath.Join(dir, "export")
+			},
+			want: dayOneJournals,
+		},
+		{
+			name: "journal file",
+			prepare: func(t *testing.T, dir string) string {
+				writeDayOneDir(t, dir, "", map[string]string{"Journal.json": dayOneFiles["Journal.json"]})
+				return filepath.Join(dir, "Journal.json")
+			},
+			want: dayOneJournals[:1],
+		},
+		{
+			name: "directory without journals",
+			prepare: func(t *testing.T, dir string) string {
+				writeDayOneDir(t, dir, "", map[string]string{"photos/0b1c.jpeg": "not a journal"})
+				return dir
+			},
+			err: "no Day One journals found",
+		},
+		{
+			name: "invalid journal in zip archive",
+			prepare: func(t *testing.T, dir string) string {
+				return writeDayOneZip(t, dir, map[string]string{"Broken.json": `{"entries": [`})
+			},
+			err: "invalid Day One journal Broken.json",
+		},
+		{
+			name: "missing path",
+			prepare: func(t *testing.T, dir string) string {
+				return filepath.Join(dir, "missing.zip")
+			},
+			err: "missing.zip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journals, err := (&dayOneImporter{}).Read(tt.prepare(t, t.TempDir()))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Read() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(journals, tt.want) {
+				t.Errorf("Read() = %+v, want %+v", journals, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package importer reads journals exported by other journaling
+// applications and saves them to database. Formats are added by
+// implementing Importer and registering it.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// Importer reads export of another application
+type Importer interface {
+	// GetName returns name importer is picked by in command line
+	GetName() string
+
+	// GetDescription returns short description of accepted export
+	GetDescription() string
+
+	// Read parses export at path into journals
+	Read(path string) ([]Journal, error)
+}
+
+// Journal is a journal read from export
+type Journal struct {
+	Name    string
+	Entries []Entry
+}
+
+// Entry is an entry read from export
+type Entry struct {
+	// Key identifies entry in export, like its ID or creation time, entry
+	// ID is derived from it so repeated import updates the same entry
+	Key       string
+	Title     string
+	Content   string
+	Tags      []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var importers = make(map[string]Importer)
+
+// Register adds an importer to the registry
+func Register(importer Importer) {
+	importers[importer.GetName()] = importer
+}
+
+// Get returns registered importer by its name
+func Get(name string) (Importer, error) {
+	importer, exists := importers[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported import format: %s. Available formats: %v", name, GetAvailableFormats())
+	}
+	return importer, nil
+}
+
+// GetAvailableFormats returns a sorted list of registered importer names
+func GetAvailableFormats() []string {
+	names := make([]string, 0, len(importers))
+	for name := range importers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats counts imported journals and entries
+type Stats struct {
+	CreatedJournals  int
+	CreatedEntries   int
+	UpdatedEntries   int
+	MovedEntries     int
+	UnchangedEntries int
+}
+
+// Import saves journals read by importer. Journals are matched with
+// existing ones by name and created when missing. Entries keep their
+// timestamps and get IDs derived from their keys, so entries imported
+// before are updated instead of duplicated. Entries imported before to
+// another journal are moved to the journal they are imported to now.
+func Import(ctx context.Context, database db.Database, importer Importer, journals []Journal) (Stats, error) {
+	var stats Stats
+
+	existing := make(map[string]string)
+	for offset := 0; ; offset += db.JOURNAL_LIST_DEFAULT_LIMIT {
+		page, err := database.ListJournals(ctx, db.OrderByCreated, false, db.JOURNAL_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return stats, err
+		}
+		for _, j := range page {
+			if _, ok := existing[j.Name]; !ok {
+				existing[j.Name] = j.Id
+			}
+		}
+		if len(page) < db.JOURNAL_LIST_DEFAULT_LIMIT {
+			break
+		}
+	}
+
+	for _, j := range journals {
+		journalId, ok := existing[j.Name]
+		if !ok {
+			journal, err := database.CreateJournal(ctx, j.Name)
+			if err != nil {
+				return stats, fmt.Errorf("failed to create journal %q: %w", j.Name, err)
+			}
+			journalId = journal.Id
+			existing[j.Name] = journalId
+			stats.CreatedJournals++
+		}
+
+		for _, e := range j.Entries {
+			outcome, err := importEntry(ctx, database, importer.GetName(), journalId, e)
+			if err != nil {
+				return stats, fmt.Errorf("failed to import entry %q of journal %q: %w", e.Title, j.Name, err)
+			}
+			switch outcome {
+			case entryCreated:
+				stats.CreatedEntries++
+			case entryUpdated:
+				stats.UpdatedEntries++
+			case entryMoved:
+				stats.MovedEntries++
+			default:
+				stats.UnchangedEntries++
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// Outcomes of importing an entry
+const (
+	entryCreated = iota
+	entryUpdated
+	entryMoved
+	entryUnchanged
+)
+
+// importEntry saves entry with its tags unless the same entry was already
+// imported
+func importEntry(ctx context.Context, database db.Database, source, journalId string, e Entry) (int, error) {
+	entry := kb.Entry{
+		Id:        db.SourceId(source + ":" + e.Key),
+		JournalId: journalId,
+		Title:     e.Title,
+		Content:   e.Content,
+		CreatedAt: e.CreatedAt.UTC().Truncate(time.Second),
+		UpdatedAt: e.UpdatedAt.UTC().Truncate(time.Second),
+	}
+	if entry.UpdatedAt.Before(entry.CreatedAt) {
+		entry.UpdatedAt = entry.CreatedAt
+	}
+	labels := db.NormalizeTagLabels(e.Tags)
+
+	current, err := database.FindEntryById(ctx, entry.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	outcome := entryCreated
+	if current != nil && current.JournalId != journalId {
+		outcome = entryMoved
+	} else if current != nil {
+		tags, err := database.ListEntryTags(ctx, journalId, entry.Id)
+		if err != nil {
+			return 0, err
+		}
+		currentLabels := make([]string, len(tags))
+		for i, t := range tags {
+			currentLabels[i] = t.Label
+		}
+
+		if current.Title == entry.Title && current.Content == entry.Content &&
+			current.CreatedAt.Equal(entry.CreatedAt) && current.UpdatedAt.Equal(entry.UpdatedAt) &&
+			slices.Equal(slices.Sorted(slices.Values(currentLabels)), slices.Sorted(slices.Values(labels))) {
+			return entryUnchanged, nil
+		}
+		outcome = entryUpdated
+	}
+
+	if _, err := database.SaveEntry(ctx, entry); err != nil {
+		return 0, err
+	}
+	if err := db.SyncEntryTags(ctx, database, journalId, entry.Id, labels); err != nil {
+		return 0, err
+	}
+	return outcome, nil
+}
@@ -0,0 +1,218 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(&jrnlImporter{})
+}
+
+// jrnlImporter reads jrnl journal files and their JSON exports made with
+// jrnl --export json. Neither of them has a journal name, file name is
+// used for it.
+type jrnlImporter struct{}
+
+func (i *jrnlImporter) GetName() string {
+	return "jrnl"
+}
+
+func (i *jrnlImporter) GetDescription() string {
+	return "jrnl plain text journal file or JSON export"
+}
+
+// jrnlExport is a JSON export of jrnl journal
+type jrnlExport struct {
+	Entries []struct {
+		Title string   `json:"title"`
+		Body  string   `json:"body"`
+		Date  string   `json:"date"`
+		Time  string   `json:"time"`
+		Tags  []string `json:"tags"`
+	} `json:"entries"`
+}
+
+// Layouts of entry dates, jrnl writes them in local time
+var jrnlTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 03:04 PM",
+	"2006-01-02 03:04:05 PM",
+}
+
+var (
+	// Entry of jrnl journal file starts with a line like "[2024-01-02 10:00] Title."
+	jrnlEntryPattern = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{1,2}:\d{2}(?::\d{2})?(?: [AaPp][Mm])?)\] ?(.*)$`)
+
+	// Tags are marked with @ or # and follow a space or start a line
+	jrnlTagPattern = regexp.MustCompile(`(?:^|[\s(])[@#]([\p{L}\p{N}_-]+)`)
+)
+
+func (i *jrnlImporter) Read(path string) ([]Journal, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var entries []Entry
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		entries, err = readJrnlExport(path)
+	} else {
+		entries, err = readJrnlFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	uniqueJrnlKeys(entries)
+
+	return []Journal{{Name: name, Entries: entries}}, nil
+}
+
+// uniqueJrnlKeys numbers keys of entries with the same minute and title in
+// order of their appearance, the first one keeps its key
+func uniqueJrnlKeys(entries []Entry) {
+	seen := make(map[string]int)
+	for i := range entries {
+		key := entries[i].Key
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			entries[i].Key = fmt.Sprintf("%s|%d", key, n)
+		}
+	}
+}
+
+// readJrnlExport reads JSON export of jrnl journal
+func readJrnlExport(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export jrnlExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid jrnl export %s: %w", path, err)
+	}
+
+	entries := make([]Entry, 0, len(export.Entries))
+	for _, e := range export.Entries {
+		createdAt, err := parseJrnlTime(strings.TrimSpace(e.Date + " " + e.Time))
+		if err != nil {
+			return nil, fmt.Errorf("invalid jrnl export %s: %w", path, err)
+		}
+
+		tags := make([]string, 0, len(e.Tags))
+		for _, t := range e.Tags {
+			tags = append(tags, strings.TrimLeft(t, "@#"))
+		}
+		entries = append(entries, newJrnlEntry(createdAt, e.Title, strings.TrimSpace(e.Body), tags))
+	}
+	return entries, nil
+}
+
+// readJrnlFile reads plain text jrnl journal, entries start with their
+// date in brackets and the first sentence is a title
+func readJrnlFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	var createdAt time.Time
+	var text strings.Builder
+	started := false
+
+	flush := func() {
+		if !started {
+			return
+		}
+		title, body := splitJrnlTitle(strings.TrimSpace(text.String()))
+		entries = append(entries, newJrnlEntry(createdAt, title, body, jrnlTags(title+"\n"+body)))
+		text.Reset()
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if match := jrnlEntryPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			flush()
+			createdAt, err = parseJrnlTime(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			started = true
+			text.WriteString(match[2])
+			text.WriteString("\n")
+			continue
+		}
+		if !started {
+			if strings.TrimSpace(scanner.Text()) != "" {
+				return nil, fmt.Errorf("%s:%d: not a jrnl journal, entries must start with [date time]", path, line)
+			}
+			continue
+		}
+		text.WriteString(scanner.Text())
+		text.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return entries, nil
+}
+
+// newJrnlEntry builds entry, jrnl does not keep modification time
+func newJrnlEntry(createdAt time.Time, title, body string, tags []string) Entry {
+	title = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(title, "* "), " *"))
+	return Entry{
+		Key:       createdAt.UTC().Format(time.RFC3339) + "|" + title,
+		Title:     title,
+		Content:   body,
+		Tags:      tags,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func parseJrnlTime(value string) (time.Time, error) {
+	for _, layout := range jrnlTimeLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(value), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// splitJrnlTitle splits text after its first sentence or line the same
+// way jrnl does
+func splitJrnlTitle(text string) (title, body string) {
+	for i, r := range text {
+		switch r {
+		case '\n':
+			return text[:i], strings.TrimSpace(text[i+1:])
+		case '.', '?', '!':
+			if i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\n' {
+				return text[:i+1], strings.TrimSpace(text[i+1:])
+			}
+		}
+	}
+	return text, ""
+}
+
+// jrnlTags returns labels of tags in text, purely numeric ones are skipped
+func jrnlTags(text string) []string {
+	var labels []string
+	for _, match := range jrnlTagPattern.FindAllStringSubmatch(text, -1) {
+		if strings.Trim(match[1], "0123456789") != "" {
+			labels = append(labels, match[1])
+		}
+	}
+	return labels
+}
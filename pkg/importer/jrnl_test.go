@@ -0,0 +1,196 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// jrnlEntry returns entry expected from jrnl at local time
+func jrnlEntry(createdAt, title, content string, tags ...string) Entry {
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", createdAt, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return Entry{
+		Key:       t.UTC().Format(time.RFC3339) + "|" + title,
+		Title:     title,
+		Content:   content,
+		Tags:      tags,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
+}
+
+// readJrnl writes data to file with name in a temporary directory and reads it
+func readJrnl(t *testing.T, name, data string) ([]Journal, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return (&jrnlImporter{}).Read(path)
+}
+
+func TestJrnlReadFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Entry
+		err  string
+	}{
+		{
+			name: "title and body",
+			data: "[2024-01-02 10:00] First day. It was cold.\nSecond line.\n",
+			want: []Entry{jrnlEntry("2024-01-02 10:00:00", "First day.", "It was cold.\nSecond line.")},
+		},
+		{
+			name: "title without body",
+			data: "\n[2024-01-02 10:00] Just a title\n\n",
+			want: []Entry{jrnlEntry("2024-01-02 10:00:00", "Just a title", "")},
+		},
+		{
+			name: "several entries",
+			data: "[2024-01-02 10:00] One? Body one.\n\n[2024-01-03 09:30:15] Two!\nBody two.\n",
+			want: []Entry{
+				jrnlEntry("2024-01-02 10:00:00", "One?", "Body one."),
+				jrnlEntry("2024-01-03 09:30:15", "Two!", "Body two."),
+			},
+		},
+		{
+			name: "12 hour clock",
+			data: "[2024-01-02 03:04 pm] Afternoon.\n",
+			want: []Entry{jrnlEntry("2024-01-02 15:04:00", "Afternoon.", "")},
+		},
+		{
+			name: "starred entry",
+			data: "[2024-01-02 10:00] * Important.\n",
+			want: []Entry{jrnlEntry("2024-01-02 10:00:00", "Important.", "")},
+		},
+		{
+			name: "tags",
+			data: "[2024-01-02 10:00] Met @anna. Talked about #work and (#ideas), not #2024 or mail@example.com.\n",
+			want: []Entry{jrnlEntry("2024-01-02 10:00:00", "Met @anna.", "Talked about #work and (#ideas), not #2024 or mail@example.com.", "anna", "work", "ideas")},
+		},
+		{
+			name: "same minute and title",
+			data: "[2024-01-02 10:00] Same. One.\n[2024-01-02 10:00] Same. Two.\n[2024-01-02 10:00] Same. Three.\n",
+			want: func() []Entry {
+				entries := []Entry{
+					jrnlEntry("2024-01-02 10:00:00", "Same.", "One."),
+					jrnlEntry("2024-01-02 10:00:00", "Same.", "Two."),
+					jrnlEntry("2024-01-02 10:00:00", "Same.", "Three."),
+				}
+				entries[1].Key += "|2"
+				entries[2].Key += "|3"
+				return entries
+			}(),
+		},
+		{
+			name: "text before first entry",
+			data: "My journal\n[2024-01-02 10:00] Title.\n",
+			err:  ":1: not a jrnl journal",
+		},
+		{
+			name: "invalid date",
+			data: "[2024-01-02 10:00] Title.\n[2024-13-02 10:00] Title.\n",
+			err:  `:2: invalid date "2024-13-02 10:00"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journals, err := readJrnl(t, "personal.txt", tt.data)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Read() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(journals) != 1 || journals[0].Name != "personal" {
+				t.Fatalf("Read() journals = %+v, want one named personal", journals)
+			}
+			if !reflect.DeepEqual(journals[0].Entries, tt.want) {
+				t.Errorf("Read() entries = %+v, want %+v", journals[0].Entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestJrnlReadExport(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Entry
+		err  string
+	}{
+		{
+			name: "entries",
+			data: `{"tags": {"@work": 1}, "entries": [
+				{"title": "First day.", "body": "It was cold.\n", "date": "2024-01-02", "time": "10:00", "tags": ["@work", "#ideas"], "starred": false},
+				{"title": "Second day.", "body": "", "date": "2024-01-03", "time": "09:05 PM", "tags": []}
+			]}`,
+			want: []Entry{
+				jrnlEntry("2024-01-02 10:00:00", "First day.", "It was cold.", "work", "ideas"),
+				jrnlEntry("2024-01-03 21:05:00", "Second day.", "", []string{}...),
+			},
+		},
+		{
+			name: "same minute and title",
+			data: `{"entries": [
+				{"title": "Same.", "body": "One.", "date": "2024-01-02", "time": "10:00"},
+				{"title": "Same.", "body": "Two.", "date": "2024-01-02", "time": "10:00"}
+			]}`,
+			want: func() []Entry {
+				entries := []Entry{
+					jrnlEntry("2024-01-02 10:00:00", "Same.", "One.", []string{}...),
+					jrnlEntry("2024-01-02 10:00:00", "Same.", "Two.", []string{}...),
+				}
+				entries[1].Key += "|2"
+				return entries
+			}(),
+		},
+		{
+			name: "no entries",
+			data: `{"entries": []}`,
+			want: []Entry{},
+		},
+		{
+			name: "invalid JSON",
+			data: `{"entries": [`,
+			err:  "invalid jrnl export",
+		},
+		{
+			name: "invalid date",
+			data: `{"entries": [{"title": "Title.", "date": "yesterday", "time": "10:00"}]}`,
+			err:  `invalid date "yesterday 10:00"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journals, err := readJrnl(t, "work.json", tt.data)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Read() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(journals) != 1 || journals[0].Name != "work" {
+				t.Fatalf("Read() journals = %+v, want one named work", journals)
+			}
+			if !reflect.DeepEqual(journals[0].Entries, tt.want) {
+				t.Errorf("Read() entries = %+v, want %+v", journals[0].Entries, tt.want)
+			}
+		})
+	}
+}
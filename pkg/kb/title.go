@@ -0,0 +1,29 @@
+package kb
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum length of entry title derived from its content
+const TITLE_MAX_LENGTH int = 80
+
+// SplitTitle derives entry title from the first non-blank line of text,
+// Markdown heading marks are dropped. The line is removed from returned
+// content unless it is too long for a title and had to be shortened.
+func SplitTitle(text string) (title, content string) {
+	text = strings.Trim(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	first, rest, _ := strings.Cut(text, "\n")
+	for strings.TrimSpace(first) == "" && rest != "" {
+		first, rest, _ = strings.Cut(rest, "\n")
+	}
+
+	title = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(first), "#"))
+	if utf8.RuneCountInString(title) <= TITLE_MAX_LENGTH {
+		return title, strings.Trim(rest, "\n")
+	}
+
+	runes := []rune(title)[:TITLE_MAX_LENGTH-1]
+	return strings.TrimSpace(string(runes)) + "…", text
+}
@@ -0,0 +1,261 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/frontmatter"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// ExportStats counts exported journals and entries
+type ExportStats struct {
+	Journals int
+	Entries  int
+}
+
+// Export writes journals to dir, a directory per journal with a file per
+// entry and an index file listing entries. Journals and entries are read
+// page by page in order of creation, only one page is kept in memory. If
+// journalIds are given only these journals are exported. Files left in dir
+// by previous exports of renamed or deleted entries and journals are
+// removed, so every entry is exported once. Only files listed in index
+// file of previous export or carrying ID of an exported entry are removed,
+// other notes in dir are kept.
+func Export(ctx context.Context, database db.Database, dir string, journalIds []string) (ExportStats, error) {
+	var stats ExportStats
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return stats, err
+	}
+
+	export := func(journal kb.Journal) error {
+		entryIds, err := exportJournal(ctx, database, dir, journal)
+		if err != nil {
+			return fmt.Errorf("failed to export journal %s: %w", journal.Id, err)
+		}
+		if err := removeRenamedJournal(dir, journal, entryIds); err != nil {
+			return fmt.Errorf("failed to remove previous export of journal %s: %w", journal.Id, err)
+		}
+		stats.Journals++
+		stats.Entries += len(entryIds)
+		return nil
+	}
+
+	if len(journalIds) > 0 {
+		for _, id := range journalIds {
+			journal, err := database.GetJournalById(ctx, id)
+			if err != nil {
+				return stats, err
+			}
+			if journal == nil {
+				return stats, db.ErrJournalNotFound
+			}
+			if err := export(*journal); err != nil {
+				return stats, err
+			}
+		}
+		return stats, nil
+	}
+
+	for offset := 0; ; offset += db.JOURNAL_LIST_DEFAULT_LIMIT {
+		page, err := database.ListJournals(ctx, db.OrderByCreated, false, db.JOURNAL_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return stats, err
+		}
+		for _, journal := range page {
+			if err := export(journal); err != nil {
+				return stats, err
+			}
+		}
+		if len(page) < db.JOURNAL_LIST_DEFAULT_LIMIT {
+			return stats, nil
+		}
+	}
+}
+
+// exportJournal writes entries of journal and its index file, returns
+// IDs of written entries
+func exportJournal(ctx context.Context, database db.Database, dir string, journal kb.Journal) (map[string]bool, error) {
+	journalDir := filepath.Join(dir, JournalDirName(journal))
+	if err := os.MkdirAll(journalDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// Index is read before it is overwritten to find files of previous export
+	previous, err := exportedFiles(journalDir, journal.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files of this export, others are left from previous ones
+	written := map[string]bool{IndexFileName: true}
+	entryIds := map[string]bool{}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "# %s\n\n", journal.Name)
+
+	for offset := 0; ; offset += db.ENTRY_LIST_DEFAULT_LIMIT {
+		page, err := database.ListEntries(ctx, journal.Id, db.OrderByCreated, false, db.ENTRY_LIST_DEFAULT_LIMIT, offset)
+		if err != nil {
+			return entryIds, err
+		}
+
+		for _, entry := range page {
+			tags, err := database.ListEntryTags(ctx, journal.Id, entry.Id)
+			if err != nil {
+				return entryIds, err
+			}
+			data, err := FormatEntry(entry, tags)
+			if err != nil {
+				return entryIds, err
+			}
+
+			name := EntryFileName(entry)
+			if err := os.WriteFile(filepath.Join(journalDir, name), data, 0o644); err != nil {
+				return entryIds, err
+			}
+			written[name] = true
+			entryIds[entry.Id] = true
+			fmt.Fprintf(&index, "- [%s](%s) %s\n", escapeLinkText(entry.Title), name, entry.CreatedAt.Format("2006-01-02"))
+		}
+
+		if len(page) < db.ENTRY_LIST_DEFAULT_LIMIT {
+			break
+		}
+	}
+
+	header := JournalHeader{
+		Id:        journal.Id,
+		Name:      journal.Name,
+		CreatedAt: journal.CreatedAt,
+		UpdatedAt: journal.UpdatedAt,
+	}
+	data, err := frontmatter.Format(header, strings.TrimSuffix(index.String(), "\n"))
+	if err != nil {
+		return entryIds, err
+	}
+	if err := os.WriteFile(filepath.Join(journalDir, IndexFileName), data, 0o644); err != nil {
+		return entryIds, err
+	}
+
+	return entryIds, removeStaleFiles(journalDir, written, previous, entryIds)
+}
+
+// Entry line of index file with link to entry file
+var indexLinkRe = regexp.MustCompile(`(?m)^- \[.*\]\(([^()/\\]+\.md)\) \d{4}-\d{2}-\d{2}$`)
+
+// exportedFiles returns names of entry files listed in index file of
+// previous export of journal, it is empty if there is no such index file
+func exportedFiles(journalDir, journalId string) (map[string]bool, error) {
+	data, err := os.ReadFile(filepath.Join(journalDir, IndexFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var header JournalHeader
+	body, err := frontmatter.Parse(data, &header)
+	if err != nil || header.Id != journalId {
+		return nil, nil
+	}
+
+	files := map[string]bool{}
+	for _, match := range indexLinkRe.FindAllStringSubmatch(body, -1) {
+		files[match[1]] = true
+	}
+	return files, nil
+}
+
+// removeStaleFiles removes files of previous exports from journal
+// directory, like files of renamed or deleted entries. Markdown files with
+// id in front matter are removed if they are listed in previous index file
+// or id is one of entryIds, other files are kept.
+func removeStaleFiles(journalDir string, written, previous, entryIds map[string]bool) error {
+	files, err := os.ReadDir(journalDir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || written[f.Name()] || !strings.EqualFold(filepath.Ext(f.Name()), ".md") {
+			continue
+		}
+
+		path := filepath.Join(journalDir, f.Name())
+		id, err := headerId(path)
+		if err != nil || id == "" || (!previous[f.Name()] && !entryIds[id]) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeRenamedJournal removes files of previous exports of journal made
+// before it was renamed, the directory itself is removed if it is left empty
+func removeRenamedJournal(dir string, journal kb.Journal, entryIds map[string]bool) error {
+	dirs, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dirs {
+		if !d.IsDir() || d.Name() == JournalDirName(journal) {
+			continue
+		}
+
+		journalDir := filepath.Join(dir, d.Name())
+		previous, err := exportedFiles(journalDir, journal.Id)
+		if err != nil {
+			return err
+		}
+		if previous == nil {
+			continue
+		}
+		if err := removeStaleFiles(journalDir, nil, previous, entryIds); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(journalDir, IndexFileName)); err != nil {
+			return err
+		}
+		if files, err := os.ReadDir(journalDir); err == nil && len(files) == 0 {
+			if err := os.Remove(journalDir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// headerId returns id from front matter of file
+func headerId(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var header struct {
+		Id string `yaml:"id"`
+	}
+	if _, err := frontmatter.Parse(data, &header); err != nil {
+		return "", err
+	}
+	return header.Id, nil
+}
+
+// escapeLinkText escapes characters closing Markdown link text
+func escapeLinkText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(text)
+}
@@ -0,0 +1,198 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// exportDB returns database with journal j1 named name and its entries
+// e1, e2 titled by titles
+func exportDB(name string, titles ...string) memoryDB {
+	database := memoryDB{
+		journals: map[string]kb.Journal{"j1": {Id: "j1", Name: name, CreatedAt: importTime, UpdatedAt: importTime}},
+		entries:  map[string]kb.Entry{},
+		tags:     map[string][]string{"e1": {"work"}},
+	}
+	for i, title := range titles {
+		id := fmt.Sprintf("e%d", i+1)
+		database.entries[id] = importEntry(id, "j1", title, "Text of "+title+"\n")
+	}
+	return database
+}
+
+// Notes of other tools with their own ids, export must keep them
+var exportForeignFiles = map[string]string{
+	"Home.md":              "---\nid: home\n---\nVault home\n",
+	"personal-j1/notes.md": "---\nid: obsidian-1\n---\nMy notes\n",
+	"personal-j1/e1.txt":   "not a note",
+}
+
+// readDir returns contents of files in dir by their slash separated paths
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      memoryDB // Database of previous export
+		foreign     map[string]string
+		removeIndex bool // Index file of previous export is removed
+		after       memoryDB
+		want        []string // Paths of files after export
+	}{
+		{
+			name:  "first export",
+			after: exportDB("Personal", "Day one", "Day two"),
+			want:  []string{"personal-j1/day-one-e1.md", "personal-j1/day-two-e2.md", "personal-j1/index.md"},
+		},
+		{
+			name:   "unchanged",
+			before: exportDB("Personal", "Day one", "Day two"),
+			after:  exportDB("Personal", "Day one", "Day two"),
+			want:   []string{"personal-j1/day-one-e1.md", "personal-j1/day-two-e2.md", "personal-j1/index.md"},
+		},
+		{
+			name:   "renamed entry",
+			before: exportDB("Personal", "Day one", "Day two"),
+			after:  exportDB("Personal", "First day", "Day two"),
+			want:   []string{"personal-j1/day-two-e2.md", "personal-j1/first-day-e1.md", "personal-j1/index.md"},
+		},
+		{
+			name:   "deleted entry",
+			before: exportDB("Personal", "Day one", "Day two"),
+			after:  exportDB("Personal", "Day one"),
+			want:   []string{"personal-j1/day-one-e1.md", "personal-j1/index.md"},
+		},
+		{
+			name:   "renamed journal",
+			before: exportDB("Personal", "Day one", "Day two"),
+			after:  exportDB("Diary", "Day one"),
+			want:   []string{"diary-j1/day-one-e1.md", "diary-j1/index.md"},
+		},
+		{
+			name:        "renamed entry without previous index",
+			before:      exportDB("Personal", "Day one", "Day two"),
+			removeIndex: true,
+			after:       exportDB("Personal", "First day"),
+			want:        []string{"personal-j1/day-two-e2.md", "personal-j1/first-day-e1.md", "personal-j1/index.md"},
+		},
+		{
+			name:    "foreign files",
+			before:  exportDB("Personal", "Day one", "Day two"),
+			foreign: exportForeignFiles,
+			after:   exportDB("Personal", "First day"),
+			want: []string{
+				"Home.md",
+				"personal-j1/e1.txt",
+				"personal-j1/first-day-e1.md",
+				"personal-j1/index.md",
+				"personal-j1/notes.md",
+			},
+		},
+		{
+			name:    "foreign files in renamed journal",
+			before:  exportDB("Personal", "Day one", "Day two"),
+			foreign: exportForeignFiles,
+			after:   exportDB("Diary", "Day one", "Day two"),
+			want: []string{
+				"Home.md",
+				"diary-j1/day-one-e1.md",
+				"diary-j1/day-two-e2.md",
+				"diary-j1/index.md",
+				"personal-j1/e1.txt",
+				"personal-j1/notes.md",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "out")
+			ctx := context.Background()
+
+			if _, err := Export(ctx, &tt.before, dir, nil); err != nil {
+				t.Fatalf("Export() before error = %v", err)
+			}
+			if tt.removeIndex {
+				if err := os.Remove(filepath.Join(dir, "personal-j1", IndexFileName)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			writeFiles(t, dir, tt.foreign)
+
+			stats, err := Export(ctx, &tt.after, dir, nil)
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if stats.Journals != len(tt.after.journals) || stats.Entries != len(tt.after.entries) {
+				t.Errorf("Export() = %+v, want %d journals and %d entries", stats, len(tt.after.journals), len(tt.after.entries))
+			}
+
+			files := readDir(t, dir)
+			if got := slices.Sorted(maps.Keys(files)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("files =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			for name, data := range tt.foreign {
+				if files[name] != data {
+					t.Errorf("foreign file %s = %q, want %q", name, files[name], data)
+				}
+			}
+		})
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	database := exportDB("Personal", "Day one", "Day two")
+	database.entries["e3"] = importEntry("e3", "j1", "Spaced", "\n\nText between blank lines\n\n")
+	dir := filepath.Join(t.TempDir(), "out")
+	if _, err := Export(context.Background(), &database, dir, nil); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// Exported journal imported back is unchanged
+	plan, err := PlanImport(context.Background(), &database, dir)
+	if err != nil {
+		t.Fatalf("PlanImport() error = %v", err)
+	}
+	for _, j := range plan.Journals {
+		if j.Action != ActionUnchanged {
+			t.Errorf("journal %s is planned to %s, want %s", j.Source, j.Action, ActionUnchanged)
+		}
+		for _, e := range j.Entries {
+			if e.Action != ActionUnchanged {
+				t.Errorf("entry %s is planned to %s, want %s", e.Source, e.Action, ActionUnchanged)
+			}
+		}
+	}
+}
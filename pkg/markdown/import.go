@@ -0,0 +1,422 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/frontmatter"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// Action is a change import makes to a journal or an entry
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionMove      Action = "move" // Entry exists in another journal
+	ActionUnchanged Action = "unchanged"
+)
+
+// ImportPlan lists changes import of a directory makes, it is built
+// without changing database so it could be reviewed first
+type ImportPlan struct {
+	Journals []JournalPlan
+}
+
+// JournalPlan is a journal built from a directory with its entries
+type JournalPlan struct {
+	Action  Action
+	Source  string // Directory relative to imported one
+	Journal kb.Journal
+	Entries []EntryPlan
+}
+
+// EntryPlan is an entry built from a file with labels of its tags
+type EntryPlan struct {
+	Action      Action
+	Source      string // File relative to imported directory
+	Entry       kb.Entry
+	Tags        []string
+	FromJournal string // Journal ID of moved entry
+}
+
+// importHeader is a front matter of imported file, it accepts headers of
+// exported entries as well as common keys of Obsidian notes
+type importHeader struct {
+	Id        string  `yaml:"id"`
+	Title     string  `yaml:"title"`
+	Tags      tagList `yaml:"tags"`
+	CreatedAt string  `yaml:"created_at"`
+	Created   string  `yaml:"created"`
+	Date      string  `yaml:"date"`
+	UpdatedAt string  `yaml:"updated_at"`
+	Updated   string  `yaml:"updated"`
+	Modified  string  `yaml:"modified"`
+}
+
+// tagList is a list of tags given as YAML sequence or as comma or space
+// separated string, leading # of labels is dropped
+type tagList []string
+
+func (t *tagList) UnmarshalYAML(value *yaml.Node) error {
+	var labels []string
+	switch value.Kind {
+	case yaml.ScalarNode:
+		labels = strings.FieldsFunc(value.Value, func(r rune) bool { return r == ',' || r == ' ' })
+	case yaml.SequenceNode:
+		if err := value.Decode(&labels); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("line %d: tags must be a list or a string", value.Line)
+	}
+
+	for _, label := range labels {
+		*t = append(*t, strings.TrimPrefix(strings.TrimSpace(label), "#"))
+	}
+	return nil
+}
+
+// Layouts of dates accepted in front matter, ones without zone are in
+// local time
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTime parses the first set value, zero time is returned if none is set
+func parseTime(values ...string) (time.Time, error) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return normalizeTime(t), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Time{}, nil
+}
+
+// normalizeTime drops precision not kept by databases, so imported and
+// stored times could be compared
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+var (
+	// Inline tag starts a line or follows a space or a bracket, like in Obsidian
+	inlineTagPattern = regexp.MustCompile(`(?m)(?:^|[\s(\[])#([\p{L}\p{N}_/-]+)`)
+	codeSpanPattern  = regexp.MustCompile("`[^`\n]*`")
+)
+
+// InlineTags returns labels of #tags in Markdown text, tags in code
+// blocks and code spans and purely numeric ones like #123 are skipped
+func InlineTags(text string) []string {
+	var labels []string
+	fenced := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		line = codeSpanPattern.ReplaceAllString(line, "")
+		for _, match := range inlineTagPattern.FindAllStringSubmatch(line, -1) {
+			label := strings.TrimRight(match[1], "/-")
+			if strings.Trim(label, "0123456789") != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// PlanImport builds plan of importing Markdown files of dir. Every
+// directory with Markdown files becomes a journal named by its path, files
+// of dir itself go to a journal named by dir. Hidden files and directories,
+// like .obsidian, are skipped. Index files written by Export keep ID and
+// name of journals. Records without ID in front matter get one derived
+// from path of their file relative to dir, so repeated import of the same
+// files updates records imported before instead of duplicating them, even
+// if dir was moved or copied to another machine.
+func PlanImport(ctx context.Context, database db.Database, dir string) (*ImportPlan, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	files := make(map[string][]string)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		parent := filepath.Dir(path)
+		if _, ok := files[parent]; !ok {
+			dirs = append(dirs, parent)
+		}
+		files[parent] = append(files[parent], path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ImportPlan{}
+	entryIds := make(map[string]string)
+	for _, path := range dirs {
+		journalPlan, err := planJournal(ctx, database, root, path, files[path])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range journalPlan.Entries {
+			if source, ok := entryIds[e.Entry.Id]; ok {
+				return nil, fmt.Errorf("%s and %s have the same id %s", source, e.Source, e.Entry.Id)
+			}
+			entryIds[e.Entry.Id] = e.Source
+		}
+
+		plan.Journals = append(plan.Journals, *journalPlan)
+	}
+
+	return plan, nil
+}
+
+// planJournal builds plan of a journal from its directory and files
+func planJournal(ctx context.Context, database db.Database, root, path string, files []string) (*JournalPlan, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.ToSlash(rel)
+	if rel == "." {
+		name = filepath.Base(root)
+	}
+	plan := &JournalPlan{
+		Source:  filepath.ToSlash(rel),
+		Journal: kb.Journal{Id: db.SourceId("journal:" + filepath.ToSlash(rel)), Name: name},
+	}
+
+	// Index file of exported journal is not an entry
+	var header JournalHeader
+	if i := slices.IndexFunc(files, func(f string) bool { return filepath.Base(f) == IndexFileName }); i >= 0 {
+		data, err := os.ReadFile(files[i])
+		if err != nil {
+			return nil, err
+		}
+		var index JournalHeader
+		if _, err := frontmatter.Parse(data, &index); err == nil && index.Name != "" {
+			header = index
+			files = slices.Delete(slices.Clone(files), i, i+1)
+			plan.Journal.Name = header.Name
+			if header.Id != "" {
+				plan.Journal.Id = header.Id
+			}
+		}
+	}
+
+	existing, err := database.GetJournalById(ctx, plan.Journal.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := normalizeTime(time.Now())
+	switch {
+	case !header.CreatedAt.IsZero():
+		plan.Journal.CreatedAt = normalizeTime(header.CreatedAt)
+		plan.Journal.UpdatedAt = normalizeTime(header.UpdatedAt)
+	case existing != nil:
+		plan.Journal.CreatedAt = normalizeTime(existing.CreatedAt)
+		plan.Journal.UpdatedAt = normalizeTime(existing.UpdatedAt)
+	default:
+		plan.Journal.CreatedAt, plan.Journal.UpdatedAt = now, now
+	}
+
+	switch {
+	case existing == nil:
+		plan.Action = ActionCreate
+	case existing.Name != plan.Journal.Name ||
+		!normalizeTime(existing.CreatedAt).Equal(plan.Journal.CreatedAt) ||
+		!normalizeTime(existing.UpdatedAt).Equal(plan.Journal.UpdatedAt):
+		plan.Action = ActionUpdate
+	default:
+		plan.Action = ActionUnchanged
+	}
+
+	for _, file := range files {
+		entryPlan, err := planEntry(ctx, database, root, file, plan.Journal.Id)
+		if err != nil {
+			return nil, err
+		}
+		plan.Entries = append(plan.Entries, *entryPlan)
+	}
+
+	return plan, nil
+}
+
+// planEntry builds plan of an entry from its file
+func planEntry(ctx context.Context, database db.Database, root, path, journalId string) (*EntryPlan, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, err
+	}
+	plan := &EntryPlan{Source: filepath.ToSlash(rel)}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header importHeader
+	content, err := frontmatter.Parse(data, &header)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", plan.Source, err)
+	}
+
+	updatedAt, err := parseTime(header.UpdatedAt, header.Updated, header.Modified)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", plan.Source, err)
+	}
+	if updatedAt.IsZero() {
+		updatedAt = normalizeTime(info.ModTime())
+	}
+	createdAt, err := parseTime(header.CreatedAt, header.Created, header.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", plan.Source, err)
+	}
+	if createdAt.IsZero() {
+		createdAt = updatedAt
+	}
+
+	title := strings.TrimSpace(header.Title)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	id := header.Id
+	if id == "" {
+		id = db.SourceId("entry:" + plan.Source)
+	}
+
+	plan.Entry = kb.Entry{
+		Id:        id,
+		JournalId: journalId,
+		Title:     title,
+		Content:   content,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+	plan.Tags = db.NormalizeTagLabels(append(header.Tags, InlineTags(content)...))
+
+	// Entry with the same ID could be in another journal, saving it moves
+	// the entry, so it is planned as a move to be reviewed
+	existing, err := database.FindEntryById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		plan.Action = ActionCreate
+		return plan, nil
+	}
+	if existing.JournalId != journalId {
+		plan.Action = ActionMove
+		plan.FromJournal = existing.JournalId
+		return plan, nil
+	}
+
+	tags, err := database.ListEntryTags(ctx, journalId, id)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(tags))
+	for i, t := range tags {
+		labels[i] = t.Label
+	}
+	slices.Sort(labels)
+
+	plan.Action = ActionUnchanged
+	if existing.Title != plan.Entry.Title || existing.Content != plan.Entry.Content ||
+		!normalizeTime(existing.CreatedAt).Equal(createdAt) || !normalizeTime(existing.UpdatedAt).Equal(updatedAt) ||
+		!slices.Equal(labels, slices.Sorted(slices.Values(plan.Tags))) {
+		plan.Action = ActionUpdate
+	}
+	return plan, nil
+}
+
+// Count returns numbers of journals and entries with the given action
+func (p *ImportPlan) Count(action Action) (journals, entries int) {
+	for _, j := range p.Journals {
+		if j.Action == action {
+			journals++
+		}
+		for _, e := range j.Entries {
+			if e.Action == action {
+				entries++
+			}
+		}
+	}
+	return journals, entries
+}
+
+// Apply saves created and updated journals and entries of the plan with
+// their tags, missing tags are created
+func (p *ImportPlan) Apply(ctx context.Context, database db.Database) error {
+	for _, j := range p.Journals {
+		if j.Action != ActionUnchanged {
+			if _, err := database.SaveJournal(ctx, j.Journal); err != nil {
+				return fmt.Errorf("failed to import journal %s: %w", j.Source, err)
+			}
+		}
+
+		for _, e := range j.Entries {
+			if e.Action == ActionUnchanged {
+				continue
+			}
+			if _, err := database.SaveEntry(ctx, e.Entry); err != nil {
+				return fmt.Errorf("failed to import entry %s: %w", e.Source, err)
+			}
+			if err := db.SyncEntryTags(ctx, database, e.Entry.JournalId, e.Entry.Id, e.Tags); err != nil {
+				return fmt.Errorf("failed to import tags of entry %s: %w", e.Source, err)
+			}
+		}
+	}
+	return nil
+}
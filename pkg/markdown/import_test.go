@@ -0,0 +1,278 @@
+package markdown
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+func TestInlineTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no tags", "Plain text", nil},
+		{"tags", "#start of line, in #middle and #end", []string{"start", "middle", "end"}},
+		{"brackets", "(#round) [#square]", []string{"round", "square"}},
+		{"nested and unicode", "#project/firn #заметка #snake_case", []string{"project/firn", "заметка", "snake_case"}},
+		{"trailing separators", "#dash- #slash/", []string{"dash", "slash"}},
+		{"headings and anchors", "# Heading\n## Sub\nlink#anchor and a#b", nil},
+		{"numeric", "issue #123 and #2024-01 but #v2", []string{"2024-01", "v2"}},
+		{"code span", "`#not` a tag, but #this is", []string{"this"}},
+		{
+			name: "code fences",
+			text: "#before\n```go\n#not a tag\n```\n#between\n  ~~~\n#skipped\n  ~~~\n#after",
+			want: []string{"before", "between", "after"},
+		},
+		{"unclosed code fence", "#before\n```\n#inside", []string{"before"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InlineTags(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InlineTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// memoryDB keeps journals, entries and their tag labels in maps, only
+// methods used by PlanImport and Export are implemented
+type memoryDB struct {
+	db.Database
+	journals map[string]kb.Journal
+	entries  map[string]kb.Entry
+	tags     map[string][]string
+}
+
+func (m *memoryDB) GetJournalById(ctx context.Context, id string) (*kb.Journal, error) {
+	if j, ok := m.journals[id]; ok {
+		return &j, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+	journals := slices.SortedFunc(maps.Values(m.journals), func(a, b kb.Journal) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id, b.Id))
+	})
+	return page(journals, limit, offset), nil
+}
+
+func (m *memoryDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	var entries []kb.Entry
+	for _, e := range m.entries {
+		if e.JournalId == journalId {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b kb.Entry) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id, b.Id))
+	})
+	return page(entries, limit, offset), nil
+}
+
+// page returns items from offset up to limit
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+func (m *memoryDB) FindEntryById(ctx context.Context, entryId string) (*kb.Entry, error) {
+	if e, ok := m.entries[entryId]; ok {
+		return &e, nil
+	}
+	return nil, nil
+}
+
+func (m *memoryDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
+	var tags []kb.Tag
+	for _, label := range m.tags[entryId] {
+		tags = append(tags, kb.Tag{Id: label, Label: label})
+	}
+	return tags, nil
+}
+
+// Time of entries in test files and database
+var importTime = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// importFile returns Markdown file with front matter of entry
+func importFile(id, title, body string) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	if id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", id)
+	}
+	fmt.Fprintf(&sb, "title: %s\ncreated_at: %s\nupdated_at: %s\n---\n%s", title, importTime.Format(time.RFC3339), importTime.Format(time.RFC3339), body)
+	return sb.String()
+}
+
+// importEntry returns entry stored in database as if it was imported
+func importEntry(id, journalId, title, content string) kb.Entry {
+	return kb.Entry{Id: id, JournalId: journalId, Title: title, Content: content, CreatedAt: importTime, UpdatedAt: importTime}
+}
+
+// Vault used by tests, journal directory names are relative to vault
+var importVault = map[string]string{
+	"Inbox.md":            importFile("", "Inbox", "Things to do #todo\n"),
+	"work/standup.md":     importFile("e-standup", "Standup", "Notes\n"),
+	"work/retro.md":       "No front matter #work",
+	"work/image.png":      "not a note",
+	".obsidian/config.md": "hidden",
+	"work/.draft.md":      "hidden",
+}
+
+// Journal IDs derived from paths in vault
+var (
+	rootJournalId = db.SourceId("journal:.")
+	workJournalId = db.SourceId("journal:work")
+)
+
+// writeFiles writes files to dir by their slash separated paths
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		database memoryDB
+		want     []string // Actions with sources of journals and entries in plan order
+		err      string
+	}{
+		{
+			name:  "new vault",
+			files: importVault,
+			want: []string{
+				"create journal . vault " + rootJournalId,
+				"create entry Inbox.md Inbox [todo]",
+				"create journal work work " + workJournalId,
+				"create entry work/retro.md retro [work]",
+				"create entry work/standup.md Standup []",
+			},
+		},
+		{
+			name:  "imported vault",
+			files: importVault,
+			database: memoryDB{
+				journals: map[string]kb.Journal{
+					rootJournalId: {Id: rootJournalId, Name: "vault", CreatedAt: importTime, UpdatedAt: importTime},
+					workJournalId: {Id: workJournalId, Name: "Work", CreatedAt: importTime, UpdatedAt: importTime},
+				},
+				entries: map[string]kb.Entry{
+					db.SourceId("entry:Inbox.md"): importEntry(db.SourceId("entry:Inbox.md"), rootJournalId, "Inbox", "Things to do #todo"),
+					"e-standup":                   importEntry("e-standup", "other", "Standup", "Notes"),
+				},
+				tags: map[string][]string{db.SourceId("entry:Inbox.md"): {"todo"}},
+			},
+			want: []string{
+				"unchanged journal . vault " + rootJournalId,
+				"unchanged entry Inbox.md Inbox [todo]",
+				"update journal work work " + workJournalId,
+				"create entry work/retro.md retro [work]",
+				"move entry work/standup.md Standup [] from other",
+			},
+		},
+		{
+			name:  "changed entry",
+			files: map[string]string{"note.md": importFile("e1", "Note", "New text #new\n")},
+			database: memoryDB{
+				journals: map[string]kb.Journal{rootJournalId: {Id: rootJournalId, Name: "vault", CreatedAt: importTime, UpdatedAt: importTime}},
+				entries:  map[string]kb.Entry{"e1": importEntry("e1", rootJournalId, "Note", "New text #new")},
+				tags:     map[string][]string{"e1": {"old"}},
+			},
+			want: []string{
+				"unchanged journal . vault " + rootJournalId,
+				"update entry note.md Note [new]",
+			},
+		},
+		{
+			name: "exported journal",
+			files: map[string]string{
+				"personal-j1/index.md":   "---\nid: j1\nname: Personal\ncreated_at: 2024-01-02T10:00:00Z\nupdated_at: 2024-01-02T10:00:00Z\n---\n# Personal\n",
+				"personal-j1/day-e1.md":  importFile("e1", "Day", "Text\n"),
+				"personal-j1/notes.md":   importFile("", "Notes", ""),
+				"personal-j1/index.txt":  "not a note",
+				"personal-j1/.hidden.md": "hidden",
+			},
+			want: []string{
+				"create journal personal-j1 Personal j1",
+				"create entry personal-j1/day-e1.md Day []",
+				"create entry personal-j1/notes.md Notes []",
+			},
+		},
+		{
+			name: "same id in two files",
+			files: map[string]string{
+				"a.md":     importFile("e1", "A", ""),
+				"sub/b.md": importFile("e1", "B", ""),
+			},
+			err: "a.md and sub/b.md have the same id e1",
+		},
+		{
+			name:  "invalid date",
+			files: map[string]string{"note.md": "---\ncreated: tomorrow\n---\n"},
+			err:   `note.md: invalid date "tomorrow"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "vault")
+			writeFiles(t, dir, tt.files)
+
+			plan, err := PlanImport(context.Background(), &tt.database, dir)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("PlanImport() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PlanImport() error = %v", err)
+			}
+
+			var got []string
+			for _, j := range plan.Journals {
+				got = append(got, fmt.Sprintf("%s journal %s %s %s", j.Action, j.Source, j.Journal.Name, j.Journal.Id))
+				for _, e := range j.Entries {
+					line := fmt.Sprintf("%s entry %s %s %v", e.Action, e.Source, e.Entry.Title, e.Tags)
+					if e.FromJournal != "" {
+						line += " from " + e.FromJournal
+					}
+					if e.Entry.JournalId != j.Journal.Id {
+						t.Errorf("entry %s is planned to journal %s, want %s", e.Source, e.Entry.JournalId, j.Journal.Id)
+					}
+					got = append(got, line)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlanImport() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
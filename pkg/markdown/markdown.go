@@ -0,0 +1,96 @@
+// Package markdown converts journals and entries to Markdown files with
+// YAML front matter and back
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kompotkot/firn/pkg/frontmatter"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// Name of journal index file in journal directory
+const IndexFileName = "index.md"
+
+// EntryHeader is a front matter of entry file
+type EntryHeader struct {
+	Id        string    `yaml:"id"`
+	Title     string    `yaml:"title"`
+	Tags      []string  `yaml:"tags,flow"`
+	CreatedAt time.Time `yaml:"created_at"`
+	UpdatedAt time.Time `yaml:"updated_at"`
+}
+
+// JournalHeader is a front matter of journal index file
+type JournalHeader struct {
+	Id        string    `yaml:"id"`
+	Name      string    `yaml:"name"`
+	CreatedAt time.Time `yaml:"created_at"`
+	UpdatedAt time.Time `yaml:"updated_at"`
+}
+
+// Maximum length of slug in bytes, it keeps file names with appended ID
+// well below file system limits
+const SLUG_MAX_LENGTH int = 64
+
+// Slugify turns title into a file name friendly string, long titles are
+// cut on a rune boundary
+func Slugify(title string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if sb.Len()+utf8.RuneLen(r) > SLUG_MAX_LENGTH {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && sb.Len() > 0 {
+			sb.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// fileName builds name from title and ID, so it stays the same between
+// exports and does not clash with other titles
+func fileName(title, id string) string {
+	if slug := Slugify(title); slug != "" {
+		return fmt.Sprintf("%s-%s", slug, id)
+	}
+	return id
+}
+
+// EntryFileName returns name of entry file
+func EntryFileName(entry kb.Entry) string {
+	return fileName(entry.Title, entry.Id) + ".md"
+}
+
+// JournalDirName returns name of journal directory
+func JournalDirName(journal kb.Journal) string {
+	return fileName(journal.Name, journal.Id)
+}
+
+// FormatEntry encodes entry with labels of its tags as Markdown document
+// with front matter
+func FormatEntry(entry kb.Entry, tags []kb.Tag) ([]byte, error) {
+	header := EntryHeader{
+		Id:        entry.Id,
+		Title:     entry.Title,
+		Tags:      make([]string, len(tags)),
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+	for i, t := range tags {
+		header.Tags[i] = t.Label
+	}
+
+	return frontmatter.Format(header, entry.Content)
+}
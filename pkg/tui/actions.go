@@ -5,45 +5,24 @@ package tui
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/kompotkot/firn/pkg/db"
 	"github.com/kompotkot/firn/pkg/kb"
 
-	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Keymap for help panel in footer
-type keymap = struct {
-	enter key.Binding
-	esc   key.Binding
-	quit  key.Binding
-}
-
-func initKeymap() keymap {
-	return keymap{
-		enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select"),
-		),
-		esc: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "back"),
-		),
-		quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-	}
-}
-
 type journalsLoadedMsg struct {
 	journals []kb.Journal
+	offset   int
 }
 
 type entriesLoadedMsg struct {
 	journalId string
+	day       time.Time // Day of calendar entries were listed for, zero for all entries
 	entries   []kb.Entry
+	offset    int
 }
 
 type entryLoadedMsg struct {
@@ -52,9 +31,14 @@ type entryLoadedMsg struct {
 	entry     *kb.Entry
 }
 
+type entryCreatedMsg struct {
+	entry *kb.Entry
+}
+
 type errMsg struct {
 	operation string
 	err       error
+	retry     tea.Cmd // Command to repeat failed load, if it could be retried
 }
 
 func (e errMsg) Error() string {
@@ -65,34 +49,42 @@ func (e errMsg) Error() string {
 }
 
 // List journals from the database and return as tea data
-func listJournals(ctx context.Context, database db.Database, orderByDesc bool, limit int, offset int) tea.Cmd {
+func listJournals(ctx context.Context, database db.Database, sort listSort, limit int, offset int) tea.Cmd {
 	return func() tea.Msg {
 		currentCtx := ctx
 		if currentCtx == nil {
 			currentCtx = context.Background()
 		}
 
-		journals, err := database.ListJournals(currentCtx, orderByDesc, limit, offset)
+		journals, err := database.ListJournals(currentCtx, sort.OrderBy, sort.Desc, limit, offset)
 		if err != nil {
-			return errMsg{operation: "listJournals", err: err}
+			return errMsg{
+				operation: "listJournals",
+				err:       err,
+				retry:     listJournals(ctx, database, sort, limit, offset),
+			}
 		}
-		return journalsLoadedMsg{journals: journals}
+		return journalsLoadedMsg{journals: journals, offset: offset}
 	}
 }
 
 // List journal entries from the database and return as tea data
-func listEntries(ctx context.Context, database db.Database, journalId string, orderByDesc bool, limit int, offset int) tea.Cmd {
+func listEntries(ctx context.Context, database db.Database, journalId string, sort listSort, limit int, offset int) tea.Cmd {
 	return func() tea.Msg {
 		currentCtx := ctx
 		if currentCtx == nil {
 			currentCtx = context.Background()
 		}
 
-		entries, err := database.ListEntries(currentCtx, journalId, orderByDesc, limit, offset)
+		entries, err := database.ListEntries(currentCtx, journalId, sort.OrderBy, sort.Desc, limit, offset)
 		if err != nil {
-			return errMsg{operation: fmt.Sprintf("listEntries(%s)", journalId), err: err}
+			return errMsg{
+				operation: fmt.Sprintf("listEntries(%s)", journalId),
+				err:       err,
+				retry:     listEntries(ctx, database, journalId, sort, limit, offset),
+			}
 		}
-		return entriesLoadedMsg{journalId: journalId, entries: entries}
+		return entriesLoadedMsg{journalId: journalId, entries: entries, offset: offset}
 	}
 }
 
@@ -106,7 +98,11 @@ func getEntryById(ctx context.Context, database db.Database, journalId, entryId
 
 		entry, err := database.GetEntryById(currentCtx, journalId, entryId)
 		if err != nil {
-			return errMsg{operation: fmt.Sprintf("getEntryById(%s,%s)", journalId, entryId), err: err}
+			return errMsg{
+				operation: fmt.Sprintf("getEntryById(%s,%s)", journalId, entryId),
+				err:       err,
+				retry:     getEntryById(ctx, database, journalId, entryId),
+			}
 		}
 		return entryLoadedMsg{journalId: journalId, entryId: entryId, entry: entry}
 	}
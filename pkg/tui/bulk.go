@@ -0,0 +1,239 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// entriesBulkDoneMsg reports entries a bulk action was applied to, it
+// could be a part of requested entries if the action failed
+type entriesBulkDoneMsg struct {
+	journalId string
+	entryIds  []string
+	done      string // Past tense of action shown in status line
+	removed   bool   // Entries left the journal
+	err       error
+}
+
+// bulkEntries applies op to entries one by one and stops at the first error
+func bulkEntries(ctx context.Context, journalId string, entryIds []string, done string, removed bool, op func(ctx context.Context, entryId string) error) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		msg := entriesBulkDoneMsg{journalId: journalId, done: done, removed: removed}
+		for _, id := range entryIds {
+			if err := op(currentCtx, id); err != nil {
+				msg.err = fmt.Errorf("entry %s: %w", id, err)
+				break
+			}
+			msg.entryIds = append(msg.entryIds, id)
+		}
+		return msg
+	}
+}
+
+// Delete entries of journal
+func bulkDelete(ctx context.Context, database db.Database, journalId string, entryIds []string) tea.Cmd {
+	return bulkEntries(ctx, journalId, entryIds, "deleted", true, func(ctx context.Context, entryId string) error {
+		return database.DeleteEntry(ctx, journalId, entryId)
+	})
+}
+
+// Move entries of journal to target journal
+func bulkMove(ctx context.Context, database db.Database, journalId string, entryIds []string, targetJournalId, targetName string) tea.Cmd {
+	done := fmt.Sprintf("moved to %q", targetName)
+	return bulkEntries(ctx, journalId, entryIds, done, true, func(ctx context.Context, entryId string) error {
+		_, err := database.MoveEntry(ctx, journalId, entryId, targetJournalId)
+		return err
+	})
+}
+
+// Assign tags to entries, tags which do not exist yet are created
+func bulkTag(ctx context.Context, database db.Database, journalId string, entryIds []string, labels []string) tea.Cmd {
+	var tagIds []string
+	done := fmt.Sprintf("tagged with %s", strings.Join(labels, ", "))
+	return bulkEntries(ctx, journalId, entryIds, done, false, func(ctx context.Context, entryId string) error {
+		if tagIds == nil {
+			tags, err := database.CreateTags(ctx, labels)
+			if err != nil {
+				return err
+			}
+			for _, t := range tags {
+				tagIds = append(tagIds, t.Id)
+			}
+		}
+		return database.AssignTagsToEntry(ctx, journalId, entryId, tagIds)
+	})
+}
+
+// Remove tag assignments from entries, unknown tags are ignored
+func bulkUntag(ctx context.Context, database db.Database, journalId string, entryIds []string, labels []string) tea.Cmd {
+	var tagIds []string
+	done := fmt.Sprintf("untagged from %s", strings.Join(labels, ", "))
+	return bulkEntries(ctx, journalId, entryIds, done, false, func(ctx context.Context, entryId string) error {
+		if tagIds == nil {
+			tags, err := database.ListTags(ctx, labels)
+			if err != nil {
+				return err
+			}
+			tagIds = []string{}
+			for _, t := range tags {
+				tagIds = append(tagIds, t.Id)
+			}
+		}
+		return database.DeAssignTagsToEntry(ctx, journalId, entryId, tagIds)
+	})
+}
+
+// parseTagLabels splits labels typed in prompt by commas and spaces
+func parseTagLabels(value string) []string {
+	return db.NormalizeTagLabels(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}))
+}
+
+// bulkTargets returns marked entries, or selected entry when none is marked
+func (m model) bulkTargets() []string {
+	if ids := m.entries.Marked(); len(ids) > 0 {
+		return ids
+	}
+	if entry, ok := m.entries.SelectedEntry(); ok {
+		return []string{entry.Id}
+	}
+	return nil
+}
+
+// entriesCount returns "1 entry" or "N entries"
+func entriesCount(n int) string {
+	if n == 1 {
+		return "1 entry"
+	}
+	return fmt.Sprintf("%d entries", n)
+}
+
+// toggleMark marks selected entry and moves cursor to the next one
+func (m *model) toggleMark() {
+	m.entries.ToggleMark()
+	m.entries.CursorDown()
+}
+
+// deleteMarked asks to confirm deletion of marked entries
+func (m *model) deleteMarked() tea.Cmd {
+	ids := m.bulkTargets()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	m.confirm(fmt.Sprintf("Delete %s?", entriesCount(len(ids))), func(m *model) tea.Cmd {
+		return bulkDelete(m.ctx, m.database, m.selectedJournalId, ids)
+	})
+	return nil
+}
+
+// tagMarked asks for tags to assign to marked entries
+func (m *model) tagMarked() tea.Cmd {
+	ids := m.bulkTargets()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	m.ask(fmt.Sprintf("Tag %s with:", entriesCount(len(ids))), func(m *model, value string) tea.Cmd {
+		labels := parseTagLabels(value)
+		if len(labels) == 0 {
+			return nil
+		}
+		return bulkTag(m.ctx, m.database, m.selectedJournalId, ids, labels)
+	})
+	return nil
+}
+
+// untagMarked asks for tags to remove from marked entries
+func (m *model) untagMarked() tea.Cmd {
+	ids := m.bulkTargets()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	m.ask(fmt.Sprintf("Remove tags from %s:", entriesCount(len(ids))), func(m *model, value string) tea.Cmd {
+		labels := parseTagLabels(value)
+		if len(labels) == 0 {
+			return nil
+		}
+		return bulkUntag(m.ctx, m.database, m.selectedJournalId, ids, labels)
+	})
+	return nil
+}
+
+// moveMarked lists loaded journals in palette to pick target of marked
+// entries, the move is confirmed once
+func (m *model) moveMarked() tea.Cmd {
+	ids := m.bulkTargets()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var commands []paletteCommand
+	for _, it := range m.journals.Items() {
+		ji, ok := it.(jItem)
+		if !ok || ji.journal.Id == m.selectedJournalId {
+			continue
+		}
+		target := ji.journal
+		commands = append(commands, paletteCommand{
+			title: target.Name,
+			run: func(m *model) tea.Cmd {
+				m.confirm(fmt.Sprintf("Move %s to %q?", entriesCount(len(ids)), target.Name), func(m *model) tea.Cmd {
+					return bulkMove(m.ctx, m.database, m.selectedJournalId, ids, target.Id, target.Name)
+				})
+				return nil
+			},
+		})
+	}
+	if len(commands) == 0 {
+		return m.status.Push(severityWarn, "No other journals to move entries to")
+	}
+
+	m.showPalette = true
+	m.palette.Open(fmt.Sprintf("Move %s to journal", entriesCount(len(ids))), commands)
+	return nil
+}
+
+// applyBulkDone updates entries list after bulk action
+func (m *model) applyBulkDone(msg entriesBulkDoneMsg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	if msg.journalId == m.selectedJournalId && len(msg.entryIds) > 0 {
+		if msg.removed {
+			m.entries.RemoveEntries(msg.entryIds)
+			if m.focusState == focusEntry && !m.viewer.Editing() {
+				m.setFocusState(focusEntries)
+			}
+			if !m.viewer.Editing() {
+				cmds = append(cmds, m.syncSelectedEntry(m.selectedEntryId))
+			}
+			m.resizeComponents()
+		} else {
+			m.entries.ClearMarks()
+		}
+	}
+
+	text := fmt.Sprintf("%s %s", entriesCount(len(msg.entryIds)), msg.done)
+	if msg.err != nil {
+		cmds = append(cmds, m.status.Push(severityError, fmt.Sprintf("%s, stopped on error: %v", text, msg.err)))
+	} else {
+		cmds = append(cmds, m.status.Push(severityInfo, text))
+	}
+
+	return tea.Batch(cmds...)
+}
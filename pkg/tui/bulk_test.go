@@ -0,0 +1,164 @@
+//go:build tui
+
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kompotkot/firn/pkg/kb"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// entriesModel returns model showing entries e1 to e4 of journal j1 with
+// entries marked by their indexes and cursor on entry at index cursor
+func entriesModel(t *testing.T, marked []int, cursor int) model {
+	t.Helper()
+	updated, _ := initModel(nil, nil, initKeymap(keymapPresets["default"]), defaultState(), Options{}).Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m := updated.(model)
+
+	m.selectedJournalId = "j1"
+	m.setFocusState(focusEntries)
+	m.entries.StartLoading()
+	entries := []kb.Entry{{Id: "e1", JournalId: "j1"}, {Id: "e2", JournalId: "j1"}, {Id: "e3", JournalId: "j1"}, {Id: "e4", JournalId: "j1"}}
+	updated, _ = m.Update(entriesLoadedMsg{journalId: "j1", entries: entries})
+	m = updated.(model)
+
+	for _, i := range marked {
+		m.entries.Select(i)
+		m.entries.ToggleMark()
+	}
+	m.entries.Select(cursor)
+	m.selectedEntryId = entries[cursor].Id
+	return m
+}
+
+// entryIds returns IDs of listed entries
+func entryIds(m model) []string {
+	var ids []string
+	for _, it := range m.entries.Items() {
+		ids = append(ids, it.(eItem).entry.Id)
+	}
+	return ids
+}
+
+func TestApplyBulkDone(t *testing.T) {
+	tests := []struct {
+		name         string
+		marked       []int
+		cursor       int
+		focus        focusState
+		msg          entriesBulkDoneMsg
+		wantEntries  []string
+		wantMarked   []string
+		wantSelected string
+		wantFocus    focusState
+		wantStatus   string
+		wantSeverity statusSeverity
+	}{
+		{
+			name:         "deleted",
+			marked:       []int{0, 1},
+			cursor:       1,
+			msg:          entriesBulkDoneMsg{journalId: "j1", entryIds: []string{"e1", "e2"}, done: "deleted", removed: true},
+			wantEntries:  []string{"e3", "e4"},
+			wantSelected: "e3",
+			wantStatus:   "2 entries deleted",
+			wantSeverity: severityInfo,
+		},
+		{
+			name:         "moved while entry is open",
+			marked:       []int{3},
+			cursor:       3,
+			focus:        focusEntry,
+			msg:          entriesBulkDoneMsg{journalId: "j1", entryIds: []string{"e4"}, done: `moved to "Work"`, removed: true},
+			wantEntries:  []string{"e1", "e2", "e3"},
+			wantSelected: "e3",
+			wantFocus:    focusEntries,
+			wantStatus:   `1 entry moved to "Work"`,
+			wantSeverity: severityInfo,
+		},
+		{
+			name:         "tagged",
+			marked:       []int{0, 2},
+			cursor:       3,
+			msg:          entriesBulkDoneMsg{journalId: "j1", entryIds: []string{"e1", "e3"}, done: "tagged with a"},
+			wantEntries:  []string{"e1", "e2", "e3", "e4"},
+			wantSelected: "e4",
+			wantStatus:   "2 entries tagged with a",
+			wantSeverity: severityInfo,
+		},
+		{
+			name:   "stopped on error",
+			marked: []int{0, 1, 2},
+			cursor: 0,
+			msg: entriesBulkDoneMsg{
+				journalId: "j1",
+				entryIds:  []string{"e1"},
+				done:      "deleted",
+				removed:   true,
+				err:       errors.New("entry e2: database is locked"),
+			},
+			wantEntries:  []string{"e2", "e3", "e4"},
+			wantMarked:   []string{"e2", "e3"},
+			wantSelected: "e2",
+			wantStatus:   "1 entry deleted, stopped on error: entry e2: database is locked",
+			wantSeverity: severityError,
+		},
+		{
+			name:         "failed on first entry",
+			marked:       []int{0},
+			cursor:       0,
+			msg:          entriesBulkDoneMsg{journalId: "j1", done: "deleted", removed: true, err: errors.New("entry e1: not found")},
+			wantEntries:  []string{"e1", "e2", "e3", "e4"},
+			wantMarked:   []string{"e1"},
+			wantSelected: "e1",
+			wantStatus:   "0 entries deleted, stopped on error: entry e1: not found",
+			wantSeverity: severityError,
+		},
+		{
+			name:         "other journal",
+			marked:       []int{1},
+			cursor:       1,
+			msg:          entriesBulkDoneMsg{journalId: "j2", entryIds: []string{"e2"}, done: "deleted", removed: true},
+			wantEntries:  []string{"e1", "e2", "e3", "e4"},
+			wantMarked:   []string{"e2"},
+			wantSelected: "e2",
+			wantStatus:   "1 entry deleted",
+			wantSeverity: severityInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := entriesModel(t, tt.marked, tt.cursor)
+			if tt.focus != "" {
+				m.setFocusState(tt.focus)
+			}
+
+			m.applyBulkDone(tt.msg)
+
+			if got := entryIds(m); !reflect.DeepEqual(got, tt.wantEntries) {
+				t.Errorf("entries = %v, want %v", got, tt.wantEntries)
+			}
+			if got := m.entries.Marked(); !reflect.DeepEqual(got, tt.wantMarked) {
+				t.Errorf("marked = %v, want %v", got, tt.wantMarked)
+			}
+			if m.selectedEntryId != tt.wantSelected {
+				t.Errorf("selected entry = %s, want %s", m.selectedEntryId, tt.wantSelected)
+			}
+			wantFocus := tt.wantFocus
+			if wantFocus == "" {
+				wantFocus = focusEntries
+			}
+			if m.focusState != wantFocus {
+				t.Errorf("focus = %s, want %s", m.focusState, wantFocus)
+			}
+			if s := m.status.current; s == nil || s.text != tt.wantStatus || s.severity != tt.wantSeverity {
+				t.Errorf("status = %+v, want %q with severity %v", s, tt.wantStatus, tt.wantSeverity)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Width of one day cell and number of week rows in month grid
+const (
+	calendarCellWidth = 3
+	calendarWeeks     = 6
+)
+
+// Rows of calendar pane: title, weekdays, weeks and bottom padding
+const calendarHeight = calendarWeeks + 3
+
+type entryDatesLoadedMsg struct {
+	journalId string
+	month     time.Time
+	dates     []time.Time
+}
+
+// List creation times of journal entries in month for calendar marks
+func listEntryDates(ctx context.Context, database db.Database, journalId string, month time.Time) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		dates, err := database.ListEntryDates(currentCtx, journalId, month, month.AddDate(0, 1, 0))
+		if err != nil {
+			return errMsg{
+				operation: fmt.Sprintf("listEntryDates(%s,%s)", journalId, month.Format("2006-01")),
+				err:       err,
+				retry:     listEntryDates(ctx, database, journalId, month),
+			}
+		}
+		return entryDatesLoadedMsg{journalId: journalId, month: month, dates: dates}
+	}
+}
+
+// List journal entries created on a day
+func listDayEntries(ctx context.Context, database db.Database, journalId string, day time.Time, sort listSort, limit int, offset int) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		entries, err := database.ListEntriesCreatedBetween(currentCtx, journalId, day, day.AddDate(0, 0, 1), sort.OrderBy, sort.Desc, limit, offset)
+		if err != nil {
+			return errMsg{
+				operation: fmt.Sprintf("listDayEntries(%s,%s)", journalId, day.Format(time.DateOnly)),
+				err:       err,
+				retry:     listDayEntries(ctx, database, journalId, day, sort, limit, offset),
+			}
+		}
+		return entriesLoadedMsg{journalId: journalId, day: day, entries: entries, offset: offset}
+	}
+}
+
+// startOfDay returns midnight of the day in local time
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// startOfMonth returns midnight of the first day of month in local time
+func startOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Local().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+}
+
+// addMonths moves day by months, day of month is clamped to the last day
+// of shorter month instead of overflowing to the next one
+func addMonths(day time.Time, months int) time.Time {
+	month := startOfMonth(day).AddDate(0, months, 0)
+	last := month.AddDate(0, 1, -1).Day()
+	return month.AddDate(0, 0, min(day.Local().Day(), last)-1)
+}
+
+// calendarPane is a month grid marking days with entries
+type calendarPane struct {
+	day     time.Time   // Selected day
+	month   time.Time   // Month of marks, it differs from day's month while loading
+	entries map[int]int // Number of entries by day of month
+	focused bool
+}
+
+func newCalendarPane() calendarPane {
+	return calendarPane{day: startOfDay(time.Now())}
+}
+
+// Day returns selected day
+func (c calendarPane) Day() time.Time {
+	return c.day
+}
+
+// Month returns month of selected day
+func (c calendarPane) Month() time.Time {
+	return startOfMonth(c.day)
+}
+
+// SetDay selects a day, it reports whether month of marks should be loaded
+func (c *calendarPane) SetDay(day time.Time) bool {
+	c.day = startOfDay(day)
+	return !c.month.Equal(c.Month())
+}
+
+// SetDates counts entries by day of month from their creation times
+func (c *calendarPane) SetDates(month time.Time, dates []time.Time) {
+	c.month = month
+	c.entries = make(map[int]int)
+	for _, d := range dates {
+		if startOfMonth(d).Equal(month) {
+			c.entries[d.Local().Day()]++
+		}
+	}
+}
+
+// Reset forgets marks, they are loaded again for another journal
+func (c *calendarPane) Reset() {
+	c.month = time.Time{}
+	c.entries = nil
+}
+
+func (c *calendarPane) SetFocused(focused bool) {
+	c.focused = focused
+}
+
+// Move returns day selected by navigation key, arrows move by day and
+// week, page keys by month and home key jumps to today
+func (c calendarPane) Move(msg tea.KeyMsg, k keymap) (time.Time, bool) {
+	switch {
+	case msg.Type == tea.KeyLeft:
+		return c.day.AddDate(0, 0, -1), true
+	case msg.Type == tea.KeyRight:
+		return c.day.AddDate(0, 0, 1), true
+	case msg.Type == tea.KeyUp || key.Matches(msg, k.up):
+		return c.day.AddDate(0, 0, -7), true
+	case msg.Type == tea.KeyDown || key.Matches(msg, k.down):
+		return c.day.AddDate(0, 0, 7), true
+	case msg.Type == tea.KeyPgUp:
+		return addMonths(c.day, -1), true
+	case msg.Type == tea.KeyPgDown:
+		return addMonths(c.day, 1), true
+	case msg.Type == tea.KeyHome:
+		return time.Now(), true
+	}
+	return c.day, false
+}
+
+// firstGridDay returns Monday of the week with the first day of month
+func (c calendarPane) firstGridDay() time.Time {
+	month := c.Month()
+	weekday := (int(month.Weekday()) + 6) % 7 // Weeks start on Monday
+	return month.AddDate(0, 0, -weekday)
+}
+
+// DayAt returns day rendered at column x and row y of the pane
+func (c calendarPane) DayAt(x, y int) (time.Time, bool) {
+	row := y - 2 // Title and weekdays
+	col := (x - calendarStyle.GetPaddingLeft()) / calendarCellWidth
+	if row < 0 || row >= calendarWeeks || x < calendarStyle.GetPaddingLeft() || col >= 7 {
+		return time.Time{}, false
+	}
+
+	day := c.firstGridDay().AddDate(0, 0, row*7+col)
+	if !startOfMonth(day).Equal(c.Month()) {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+func (c calendarPane) View() string {
+	titleStyle := listTitleStyle
+	if !c.focused {
+		titleStyle = listBlurredTitleStyle
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(c.day.Format("January 2006")))
+	sb.WriteString("\n")
+	sb.WriteString(calendarWeekdayStyle.Render(" Mo Tu We Th Fr Sa Su"))
+
+	month := c.Month()
+	today := startOfDay(time.Now())
+	day := c.firstGridDay()
+	for week := 0; week < calendarWeeks; week++ {
+		sb.WriteString("\n")
+		for weekday := 0; weekday < 7; weekday++ {
+			cell := fmt.Sprintf("%*d", calendarCellWidth, day.Day())
+
+			style := calendarDayStyle
+			switch {
+			case !startOfMonth(day).Equal(month):
+				cell = strings.Repeat(" ", calendarCellWidth)
+			case day.Equal(c.day):
+				style = calendarSelectedStyle
+			case c.entries[day.Day()] > 0 && c.month.Equal(month):
+				style = calendarMarkedStyle
+			}
+			if day.Equal(today) {
+				style = style.Underline(true)
+			}
+
+			sb.WriteString(style.Render(cell))
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	return calendarStyle.Height(calendarHeight).Render(sb.String())
+}
+
+// entriesDay returns day entries list is filtered by, zero time when all
+// entries are listed
+func (m model) entriesDay() time.Time {
+	if !m.showCalendar {
+		return time.Time{}
+	}
+	return m.calendar.Day()
+}
+
+// toggleCalendar shows or hides calendar of selected journal, entries list
+// is reloaded for selected day or for all days
+func (m *model) toggleCalendar() tea.Cmd {
+	if !m.entriesViewActive() {
+		return nil
+	}
+
+	m.showCalendar = !m.showCalendar
+	next := focusEntries
+	if m.showCalendar {
+		next = focusCalendar
+	}
+	m.setFocusState(next)
+
+	cmds := []tea.Cmd{m.reloadEntries()}
+	if m.showCalendar {
+		m.calendar.Reset()
+		cmds = append(cmds, listEntryDates(m.ctx, m.database, m.selectedJournalId, m.calendar.Month()))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// selectDay selects calendar day and lists its entries
+func (m *model) selectDay(day time.Time) tea.Cmd {
+	cmds := []tea.Cmd{}
+	if m.calendar.SetDay(day) {
+		cmds = append(cmds, listEntryDates(m.ctx, m.database, m.selectedJournalId, m.calendar.Month()))
+	}
+	cmds = append(cmds, m.reloadEntries())
+
+	return tea.Batch(cmds...)
+}
+
+// reloadEntries clears entries list and loads it from the first page
+func (m *model) reloadEntries() tea.Cmd {
+	m.entries.Clear()
+	m.entries.SetTitle(m.entriesTitle())
+	m.selectedEntryId = ""
+	m.viewer.SetContent("")
+	m.resizeComponents()
+
+	return m.loadMoreEntries()
+}
+
+// entriesTitle returns title of entries list for selected journal and day
+func (m model) entriesTitle() string {
+	title := fmt.Sprintf("%s entries", m.selectedJournalName)
+	if day := m.entriesDay(); !day.IsZero() {
+		title = fmt.Sprintf("%s on %s", title, day.Format("Mon, 02 Jan 2006"))
+	}
+	return fmt.Sprintf("%s (%s)", title, m.entriesSort.label("title"))
+}
+
+// entriesColumnView renders calendar above entries list when it is shown
+func (m model) entriesColumnView() string {
+	if !m.showCalendar {
+		return m.entries.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.calendar.View(), m.entries.View())
+}
@@ -0,0 +1,133 @@
+//go:build tui
+
+package tui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// localDay returns midnight of a day in local time
+func localDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestCalendarMove(t *testing.T) {
+	keys := initKeymap(keymapPresets["default"])
+
+	tests := []struct {
+		name  string
+		day   time.Time
+		msg   tea.KeyMsg
+		want  time.Time
+		moved bool
+	}{
+		{"previous day", localDay(2024, 3, 1), tea.KeyMsg{Type: tea.KeyLeft}, localDay(2024, 2, 29), true},
+		{"next day", localDay(2024, 12, 31), tea.KeyMsg{Type: tea.KeyRight}, localDay(2025, 1, 1), true},
+		{"previous week", localDay(2024, 3, 5), tea.KeyMsg{Type: tea.KeyUp}, localDay(2024, 2, 27), true},
+		{"previous week by key", localDay(2024, 3, 5), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")}, localDay(2024, 2, 27), true},
+		{"next week", localDay(2024, 3, 31), tea.KeyMsg{Type: tea.KeyDown}, localDay(2024, 4, 7), true},
+		{"previous month", localDay(2024, 5, 15), tea.KeyMsg{Type: tea.KeyPgUp}, localDay(2024, 4, 15), true},
+		{"previous month from year start", localDay(2024, 1, 10), tea.KeyMsg{Type: tea.KeyPgUp}, localDay(2023, 12, 10), true},
+		{"previous shorter month", localDay(2024, 3, 31), tea.KeyMsg{Type: tea.KeyPgUp}, localDay(2024, 2, 29), true},
+		{"next shorter month", localDay(2023, 1, 31), tea.KeyMsg{Type: tea.KeyPgDown}, localDay(2023, 2, 28), true},
+		{"next month from year end", localDay(2024, 12, 31), tea.KeyMsg{Type: tea.KeyPgDown}, localDay(2025, 1, 31), true},
+		{"other key", localDay(2024, 3, 31), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, localDay(2024, 3, 31), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCalendarPane()
+			c.SetDay(tt.day)
+
+			got, moved := c.Move(tt.msg, keys)
+			if !got.Equal(tt.want) || moved != tt.moved {
+				t.Errorf("Move() = %v, %v, want %v, %v", got, moved, tt.want, tt.moved)
+			}
+		})
+	}
+}
+
+func TestCalendarDayAt(t *testing.T) {
+	// March 2024 starts on Friday, grid starts on Monday 26 February
+	left := calendarStyle.GetPaddingLeft()
+
+	tests := []struct {
+		name string
+		x, y int
+		want time.Time
+		ok   bool
+	}{
+		{"first day", left + 4*calendarCellWidth, 2, localDay(2024, 3, 1), true},
+		{"last column of cell", left + 7*calendarCellWidth - 1, 2, localDay(2024, 3, 3), true},
+		{"last day", left + 6*calendarCellWidth, 6, localDay(2024, 3, 31), true},
+		{"day of previous month", left, 2, time.Time{}, false},
+		{"day of next month", left, 7, time.Time{}, false},
+		{"weekdays row", left + 4*calendarCellWidth, 1, time.Time{}, false},
+		{"padding", left - 1, 3, time.Time{}, false},
+		{"right of grid", left + 7*calendarCellWidth, 3, time.Time{}, false},
+		{"below grid", left, 2 + calendarWeeks, time.Time{}, false},
+	}
+
+	c := newCalendarPane()
+	c.SetDay(localDay(2024, 3, 15))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.DayAt(tt.x, tt.y)
+			if !got.Equal(tt.want) || ok != tt.ok {
+				t.Errorf("DayAt(%d, %d) = %v, %v, want %v, %v", tt.x, tt.y, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCalendarFirstGridDay(t *testing.T) {
+	tests := []struct {
+		name string
+		day  time.Time
+		want time.Time
+	}{
+		{"month starts on Monday", localDay(2024, 1, 20), localDay(2024, 1, 1)},
+		{"month starts on Sunday", localDay(2024, 9, 30), localDay(2024, 8, 26)},
+		{"month starts midweek", localDay(2024, 3, 1), localDay(2024, 2, 26)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCalendarPane()
+			c.SetDay(tt.day)
+			if got := c.firstGridDay(); !got.Equal(tt.want) {
+				t.Errorf("firstGridDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarSetDates(t *testing.T) {
+	month := localDay(2024, 3, 1)
+	dates := []time.Time{
+		localDay(2024, 2, 29).Add(23 * time.Hour),
+		localDay(2024, 3, 1).Add(10 * time.Hour),
+		localDay(2024, 3, 1).Add(11 * time.Hour).UTC(),
+		localDay(2024, 3, 31).Add(23*time.Hour + 30*time.Minute),
+		localDay(2024, 4, 1),
+	}
+
+	c := newCalendarPane()
+	if !c.SetDay(localDay(2024, 3, 10)) {
+		t.Errorf("SetDay() of month without marks = false, want true")
+	}
+	c.SetDates(month, dates)
+	if want := map[int]int{1: 2, 31: 1}; !reflect.DeepEqual(c.entries, want) {
+		t.Errorf("SetDates() counts = %v, want %v", c.entries, want)
+	}
+	if c.SetDay(localDay(2024, 3, 31)) {
+		t.Errorf("SetDay() in month with marks = true, want false")
+	}
+	if !c.SetDay(localDay(2024, 4, 1)) {
+		t.Errorf("SetDay() in next month = false, want true")
+	}
+}
@@ -0,0 +1,134 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type clipboardCopiedMsg struct {
+	what  string // Description of copied text shown in status line
+	osc52 bool   // Text was sent to terminal with OSC52 sequence
+}
+
+// terminalOutput is output of the program which OSC52 sequences are also
+// written to, writes are serialized so a sequence written from a command
+// does not end up inside a frame. It stays a file for TTY detection.
+type terminalOutput struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (o *terminalOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(p)
+}
+
+var output = &terminalOutput{File: os.Stdout}
+
+// remoteSession reports whether TUI runs over SSH, where system clipboard
+// belongs to remote host and terminal clipboard is preferred
+func remoteSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// writeOSC52 asks terminal to put text into its clipboard, sequence is
+// wrapped for tmux and screen to be passed through to outer terminal
+func writeOSC52(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+
+	// Whole sequence is written at once to be kept between frames
+	_, err := io.WriteString(output, seq.String())
+	return err
+}
+
+// writeClipboard puts text into system clipboard and falls back to OSC52
+// when it is not available, it reports whether OSC52 was used
+func writeClipboard(text string) (bool, error) {
+	if !remoteSession() && !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return false, nil
+		}
+	}
+
+	if err := writeOSC52(text); err != nil {
+		return true, fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return true, nil
+}
+
+// copyText copies text described by what to clipboard
+func copyText(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		viaOSC52, err := writeClipboard(text)
+		if err != nil {
+			return errMsg{operation: fmt.Sprintf("copy %s", what), err: err}
+		}
+		return clipboardCopiedMsg{what: what, osc52: viaOSC52}
+	}
+}
+
+// copyEntryContent loads entry and copies its content to clipboard
+func copyEntryContent(ctx context.Context, database db.Database, journalId, entryId string) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		operation := fmt.Sprintf("copyEntryContent(%s,%s)", journalId, entryId)
+
+		entry, err := database.GetEntryById(currentCtx, journalId, entryId)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+		if entry == nil {
+			return errMsg{operation: operation, err: db.ErrEntryNotFound}
+		}
+
+		return copyText(entry.Content, fmt.Sprintf("content of %q", entry.Title))()
+	}
+}
+
+// yankSelected copies content of selected entry, it is not done while entry
+// is edited as unsaved changes are not in the database yet
+func (m *model) yankSelected() tea.Cmd {
+	if !m.entrySelected() {
+		return nil
+	}
+	if m.viewer.Editing() {
+		return m.status.Push(severityWarn, "Save or cancel edits to copy entry content")
+	}
+	return copyEntryContent(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId)
+}
+
+// yankSelectedId copies ID of selected journal or entry
+func (m *model) yankSelectedId() tea.Cmd {
+	switch {
+	case m.focusState == focusJournals:
+		if j, ok := m.journals.SelectedJournal(); ok {
+			return copyText(j.Id, fmt.Sprintf("ID of journal %q", j.Name))
+		}
+	case m.entrySelected() && !m.viewer.Editing():
+		return copyText(m.selectedEntryId, "entry ID")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build tui
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteOSC52(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	orig := output
+	output = &terminalOutput{File: f}
+	t.Cleanup(func() { output = orig })
+
+	// Frames are written while sequences are sent from other goroutines
+	frame := strings.Repeat("frame ", 1000) + "\n"
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			output.Write([]byte(frame))
+		}()
+		go func() {
+			defer wg.Done()
+			if err := writeOSC52("copied"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := "\x1b]52;c;Y29waWVk\x07"
+	rest := strings.ReplaceAll(string(data), seq, "")
+	if got := strings.Count(string(data), seq); got != 20 {
+		t.Errorf("output has %d sequences, want 20", got)
+	}
+	if rest != strings.Repeat(frame, 20) {
+		t.Error("frames are split by sequences")
+	}
+}
+
+func TestYankSelected(t *testing.T) {
+	m := entriesModel(t, nil, 0)
+	if cmd := m.yankSelected(); cmd == nil {
+		t.Error("yankSelected() = nil, want copy command")
+	}
+
+	m.setFocusState(focusEntry)
+	m.startEditing()
+	m.yankSelected()
+	if s := m.status.current; s == nil || s.severity != severityWarn || !strings.Contains(s.text, "edits") {
+		t.Errorf("status = %+v, want warning about edits", s)
+	}
+}
@@ -2,21 +2,13 @@
 
 package tui
 
-import (
-	"os"
-	"strconv"
-)
-
-func initDebug() bool {
-	var debugActive bool
-	var err error
-
-	debugActiveRaw := os.Getenv("TUI_DEBUG_ACTIVE")
-	debugActive, err = strconv.ParseBool(debugActiveRaw)
-	if err != nil {
-		// TODO(kompotkot): Handle by logger here
-		// fmt.Printf("Error parsing debug variable '%s', set to %v, err: %v\n", debugActiveRaw, debugActive, err)
-	}
-
-	return debugActive
+import "time"
+
+// Options are TUI settings resolved from firn configuration
+type Options struct {
+	Theme           string        // Base theme, picked by terminal background when empty
+	ThemeFile       string        // Theme config file, default location is used when empty
+	KeymapFile      string        // Keymap config file, default location is used when empty
+	RefreshInterval time.Duration // Interval of polling the database, zero disables live refresh
+	Debug           bool
 }
@@ -0,0 +1,285 @@
+//go:build tui
+
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	"github.com/BurntSushi/toml"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How often unsaved content of edited entry is written to drafts file
+const draftAutosaveInterval = 5 * time.Second
+
+// draft is unsaved content of an entry edited in the TUI
+type draft struct {
+	EntryId   string    `toml:"-"`
+	JournalId string    `toml:"journal_id"`
+	Content   string    `toml:"content"`
+	SavedAt   time.Time `toml:"saved_at"`
+}
+
+// draftsFile is a structure of drafts file, drafts are keyed by entry ID
+type draftsFile struct {
+	Drafts map[string]draft `toml:"drafts"`
+}
+
+// draftsMu serializes read-modify-write of drafts file by concurrent commands
+var draftsMu sync.Mutex
+
+type draftTickMsg struct {
+	seq int // Editing session the tick belongs to
+}
+
+type draftFoundMsg struct {
+	draft draft
+}
+
+// draftsFilePath returns path to drafts file next to state file,
+// by default ~/.local/state/firn/drafts.toml
+func draftsFilePath() string {
+	path := stateFilePath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "drafts.toml")
+}
+
+func readDrafts(path string) (draftsFile, error) {
+	drafts := draftsFile{Drafts: make(map[string]draft)}
+	if _, err := toml.DecodeFile(path, &drafts); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return drafts, fmt.Errorf("failed to read drafts file: %w", err)
+	}
+	if drafts.Drafts == nil {
+		drafts.Drafts = make(map[string]draft)
+	}
+	return drafts, nil
+}
+
+// writeDrafts replaces drafts file through temporary file, so a crash while
+// writing does not lose previous drafts
+func writeDrafts(path string, drafts draftsFile) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(drafts); err != nil {
+		return fmt.Errorf("failed to encode drafts: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write drafts file: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// storeDraft adds or replaces draft of an entry
+func storeDraft(d draft) error {
+	path := draftsFilePath()
+	if path == "" {
+		return errors.New("state directory is not available")
+	}
+
+	draftsMu.Lock()
+	defer draftsMu.Unlock()
+
+	drafts, err := readDrafts(path)
+	if err != nil {
+		return err
+	}
+	drafts.Drafts[d.EntryId] = d
+
+	return writeDrafts(path, drafts)
+}
+
+// removeDraft deletes draft of an entry if it exists
+func removeDraft(entryId string) error {
+	path := draftsFilePath()
+	if path == "" {
+		return nil
+	}
+
+	draftsMu.Lock()
+	defer draftsMu.Unlock()
+
+	drafts, err := readDrafts(path)
+	if err != nil {
+		return err
+	}
+	if _, ok := drafts.Drafts[entryId]; !ok {
+		return nil
+	}
+	delete(drafts.Drafts, entryId)
+
+	return writeDrafts(path, drafts)
+}
+
+// draftTick schedules next autosave of editing session seq
+func draftTick(seq int) tea.Cmd {
+	return tea.Tick(draftAutosaveInterval, func(time.Time) tea.Msg {
+		return draftTickMsg{seq: seq}
+	})
+}
+
+// saveDraft writes unsaved content of an entry to drafts file
+func saveDraft(journalId, entryId, content string) tea.Cmd {
+	return func() tea.Msg {
+		d := draft{EntryId: entryId, JournalId: journalId, Content: content, SavedAt: time.Now()}
+		if err := storeDraft(d); err != nil {
+			return errMsg{operation: fmt.Sprintf("saveDraft(%s,%s)", journalId, entryId), err: err}
+		}
+		return nil
+	}
+}
+
+// discardDraft deletes draft of an entry from drafts file
+func discardDraft(entryId string) tea.Cmd {
+	return func() tea.Msg {
+		if err := removeDraft(entryId); err != nil {
+			return errMsg{operation: fmt.Sprintf("discardDraft(%s)", entryId), err: err}
+		}
+		return nil
+	}
+}
+
+// findDraft looks up draft of an entry left by previous session
+func findDraft(journalId, entryId string) tea.Cmd {
+	return func() tea.Msg {
+		path := draftsFilePath()
+		if path == "" {
+			return nil
+		}
+
+		draftsMu.Lock()
+		drafts, err := readDrafts(path)
+		draftsMu.Unlock()
+		if err != nil {
+			return errMsg{operation: fmt.Sprintf("findDraft(%s,%s)", journalId, entryId), err: err}
+		}
+
+		d, ok := drafts.Drafts[entryId]
+		if !ok || d.JournalId != journalId {
+			return nil
+		}
+		d.EntryId = entryId
+
+		return draftFoundMsg{draft: d}
+	}
+}
+
+// Save content edited in the TUI keeping entry title
+func saveEntryContent(ctx context.Context, database db.Database, journalId, entryId, title, content string) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		entry, err := database.UpdateEntry(currentCtx, journalId, entryId, title, content)
+		if err != nil {
+			return errMsg{
+				operation: fmt.Sprintf("saveEntryContent(%s,%s)", journalId, entryId),
+				err:       fmt.Errorf("%w, changes are kept as draft", err),
+			}
+		}
+		return entrySavedMsg{entry: entry}
+	}
+}
+
+// startEditing switches viewer to textarea and starts autosave of drafts
+func (m *model) startEditing() tea.Cmd {
+	if !m.entrySelected() {
+		return nil
+	}
+
+	m.viewer.StartEditing()
+	m.draftSeq++
+	m.lastDraft = ""
+
+	return draftTick(m.draftSeq)
+}
+
+// autosaveDraft writes edited content to drafts file if it changed since
+// the last autosave
+func (m *model) autosaveDraft() tea.Cmd {
+	if !m.entrySelected() || !m.viewer.Modified() {
+		return nil
+	}
+
+	content := m.viewer.Value()
+	if content == m.lastDraft {
+		return nil
+	}
+	m.lastDraft = content
+
+	return saveDraft(m.selectedJournalId, m.selectedEntryId, content)
+}
+
+// saveEdited saves content of selected entry edited in the TUI. Content is
+// written to draft first and saved afterwards, so the draft is kept if the
+// save fails and is not written again after it is discarded on success.
+func (m *model) saveEdited() tea.Cmd {
+	if !m.entrySelected() || !m.viewer.Modified() {
+		return nil
+	}
+
+	entry, ok := m.entries.Entry(m.selectedEntryId)
+	if !ok {
+		return nil
+	}
+
+	return tea.Sequence(
+		m.autosaveDraft(),
+		saveEntryContent(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId, entry.Title, m.viewer.Value()),
+	)
+}
+
+// hasDraft reports whether draft of the selected entry could be restored
+func (m model) hasDraft() bool {
+	return m.pendingDraft != nil && m.pendingDraft.EntryId == m.selectedEntryId && !m.viewer.Editing()
+}
+
+// restoreDraft opens draft of selected entry in textarea to be saved
+func (m *model) restoreDraft() tea.Cmd {
+	if !m.hasDraft() {
+		return nil
+	}
+
+	d := *m.pendingDraft
+	m.pendingDraft = nil
+	m.setFocusState(focusEntry)
+	m.viewer.SetDraft(d.Content)
+	m.resizeComponents()
+
+	return tea.Batch(
+		m.startEditing(),
+		m.status.Push(severityInfo, fmt.Sprintf("Draft restored, press %s to save it", m.keys.save.Help().Key)),
+	)
+}
+
+// dropDraft deletes draft of selected entry without restoring it
+func (m *model) dropDraft() tea.Cmd {
+	if !m.hasDraft() {
+		return nil
+	}
+
+	entryId := m.pendingDraft.EntryId
+	m.pendingDraft = nil
+
+	return tea.Batch(
+		discardDraft(entryId),
+		m.status.Push(severityInfo, "Draft discarded"),
+	)
+}
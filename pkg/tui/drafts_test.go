@@ -0,0 +1,127 @@
+//go:build tui
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDrafts(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string   // Drafts file before test, if set
+		store     []draft  // Drafts stored in order
+		remove    []string // Entry IDs of drafts removed after storing
+		journalId string
+		entryId   string
+		want      string // Restored content, empty if draft is not found
+		err       string
+	}{
+		{
+			name:      "no drafts file",
+			journalId: "j1",
+			entryId:   "e1",
+		},
+		{
+			name:      "restore",
+			store:     []draft{{EntryId: "e1", JournalId: "j1", Content: "Unsaved\ntext\n"}},
+			journalId: "j1",
+			entryId:   "e1",
+			want:      "Unsaved\ntext\n",
+		},
+		{
+			name: "latest draft",
+			store: []draft{
+				{EntryId: "e1", JournalId: "j1", Content: "First"},
+				{EntryId: "e2", JournalId: "j1", Content: "Other"},
+				{EntryId: "e1", JournalId: "j1", Content: "Second"},
+			},
+			journalId: "j1",
+			entryId:   "e1",
+			want:      "Second",
+		},
+		{
+			name:      "draft of other journal",
+			store:     []draft{{EntryId: "e1", JournalId: "j1", Content: "Unsaved"}},
+			journalId: "j2",
+			entryId:   "e1",
+		},
+		{
+			name: "clear",
+			store: []draft{
+				{EntryId: "e1", JournalId: "j1", Content: "First"},
+				{EntryId: "e2", JournalId: "j1", Content: "Other"},
+			},
+			remove:    []string{"e1"},
+			journalId: "j1",
+			entryId:   "e1",
+		},
+		{
+			name: "clear keeps other drafts",
+			store: []draft{
+				{EntryId: "e1", JournalId: "j1", Content: "First"},
+				{EntryId: "e2", JournalId: "j1", Content: "Other"},
+			},
+			remove:    []string{"e1", "e3"},
+			journalId: "j1",
+			entryId:   "e2",
+			want:      "Other",
+		},
+		{
+			name:      "invalid drafts file",
+			file:      "drafts = [",
+			journalId: "j1",
+			entryId:   "e1",
+			err:       "failed to read drafts file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			if tt.file != "" {
+				path := draftsFilePath()
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, d := range tt.store {
+				if err := storeDraft(d); err != nil {
+					t.Fatalf("storeDraft() error = %v", err)
+				}
+			}
+			for _, id := range tt.remove {
+				if err := removeDraft(id); err != nil {
+					t.Fatalf("removeDraft() error = %v", err)
+				}
+			}
+
+			switch msg := findDraft(tt.journalId, tt.entryId)().(type) {
+			case errMsg:
+				if tt.err == "" || !strings.Contains(msg.Error(), tt.err) {
+					t.Fatalf("findDraft() error = %v, want containing %q", msg.err, tt.err)
+				}
+			case draftFoundMsg:
+				if tt.err != "" {
+					t.Fatalf("findDraft() = %+v, want error %q", msg.draft, tt.err)
+				}
+				if msg.draft.Content != tt.want || msg.draft.EntryId != tt.entryId || msg.draft.JournalId != tt.journalId {
+					t.Errorf("findDraft() = %+v, want content %q of entry %s in journal %s", msg.draft, tt.want, tt.entryId, tt.journalId)
+				}
+			case nil:
+				if tt.want != "" || tt.err != "" {
+					t.Errorf("findDraft() found nothing, want content %q or error %q", tt.want, tt.err)
+				}
+			default:
+				t.Fatalf("findDraft() = %T", msg)
+			}
+		})
+	}
+}
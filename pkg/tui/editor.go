@@ -0,0 +1,201 @@
+//go:build tui
+
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/editor"
+	"github.com/kompotkot/firn/pkg/frontmatter"
+	"github.com/kompotkot/firn/pkg/kb"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Title in file of new entry opened in external editor
+const newEntryTitle = "Untitled"
+
+// entryFrontMatter is a header of the file opened in external editor
+type entryFrontMatter struct {
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags,flow"`
+}
+
+type editorReadyMsg struct {
+	journalId string
+	entryId   string // Empty for new entry, which is created on save
+	path      string
+	original  []byte // File content before editing
+}
+
+type editorFinishedMsg struct {
+	editorReadyMsg
+	err error
+}
+
+type entrySavedMsg struct {
+	entry *kb.Entry
+}
+
+// Write entry with its tags to a temporary file to be opened in external editor
+func prepareEntryFile(ctx context.Context, database db.Database, journalId, entryId string) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		operation := fmt.Sprintf("prepareEntryFile(%s,%s)", journalId, entryId)
+
+		entry, err := database.GetEntryById(currentCtx, journalId, entryId)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+		if entry == nil {
+			return errMsg{operation: operation, err: db.ErrEntryNotFound}
+		}
+
+		tags, err := database.ListEntryTags(currentCtx, journalId, entryId)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+
+		header := entryFrontMatter{Title: entry.Title, Tags: make([]string, len(tags))}
+		for i, t := range tags {
+			header.Tags[i] = t.Label
+		}
+
+		msg, err := writeEntryFile(header, entry.Content)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+		msg.journalId, msg.entryId = journalId, entryId
+		return msg
+	}
+}
+
+// Write template of new entry to a temporary file to be opened in external
+// editor, the entry is created only when the file is saved with changes
+func prepareNewEntryFile(journalId string) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := writeEntryFile(entryFrontMatter{Title: newEntryTitle, Tags: []string{}}, "")
+		if err != nil {
+			return errMsg{operation: fmt.Sprintf("prepareNewEntryFile(%s)", journalId), err: err}
+		}
+		msg.journalId = journalId
+		return msg
+	}
+}
+
+// writeEntryFile writes header and content to a temporary file
+func writeEntryFile(header entryFrontMatter, content string) (editorReadyMsg, error) {
+	data, err := frontmatter.Format(header, content)
+	if err != nil {
+		return editorReadyMsg{}, err
+	}
+
+	f, err := os.CreateTemp("", "firn-*.md")
+	if err != nil {
+		return editorReadyMsg{}, err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return editorReadyMsg{}, err
+	}
+
+	return editorReadyMsg{path: f.Name(), original: data}, nil
+}
+
+// Suspend the program and open entry file in external editor
+func openEditor(msg editorReadyMsg) tea.Cmd {
+	return tea.ExecProcess(editor.Command(msg.path), func(err error) tea.Msg {
+		return editorFinishedMsg{editorReadyMsg: msg, err: err}
+	})
+}
+
+// Parse file edited in external editor and save entry with its tags, new
+// entry is created only if its file was changed. The file is kept if it could
+// not be saved, so changes are not lost. If tags of a new entry could not be
+// saved, the entry is listed and retry saves the file as its update.
+func saveEntryFile(ctx context.Context, database db.Database, msg editorFinishedMsg) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		operation := fmt.Sprintf("saveEntryFile(%s,%s)", msg.journalId, msg.entryId)
+
+		if msg.err != nil {
+			os.Remove(msg.path)
+			return errMsg{operation: operation, err: msg.err}
+		}
+
+		data, err := os.ReadFile(msg.path)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+
+		// Nothing to save if file was not changed
+		if bytes.Equal(data, msg.original) {
+			os.Remove(msg.path)
+			return nil
+		}
+
+		var header entryFrontMatter
+		content, err := frontmatter.Parse(data, &header)
+		if err != nil {
+			return errMsg{operation: operation, err: fmt.Errorf("%w, changes are kept in %s", err, msg.path)}
+		}
+
+		title := strings.TrimSpace(header.Title)
+		if title == "" {
+			return errMsg{operation: operation, err: fmt.Errorf("entry title could not be empty, changes are kept in %s", msg.path)}
+		}
+
+		var entry *kb.Entry
+		if msg.entryId == "" {
+			entry, err = database.CreateEntry(currentCtx, msg.journalId, title, content)
+		} else {
+			entry, err = database.UpdateEntry(currentCtx, msg.journalId, msg.entryId, title, content)
+		}
+		if err != nil {
+			return errMsg{operation: operation, err: fmt.Errorf("%w, changes are kept in %s", err, msg.path)}
+		}
+
+		if err := db.SyncEntryTags(currentCtx, database, msg.journalId, entry.Id, header.Tags); err != nil {
+			if msg.entryId != "" {
+				return errMsg{operation: operation, err: fmt.Errorf("%w, changes are kept in %s", err, msg.path), retry: saveEntryFile(ctx, database, msg)}
+			}
+
+			// Entry is already created, retry updates it instead of creating
+			// a duplicate
+			created := entry
+			msg.entryId = entry.Id
+			return tea.Batch(
+				func() tea.Msg { return entryCreatedMsg{entry: created} },
+				func() tea.Msg {
+					return errMsg{
+						operation: operation,
+						err:       fmt.Errorf("entry %s is created without tags: %w, changes are kept in %s", created.Id, err, msg.path),
+						retry:     saveEntryFile(ctx, database, msg),
+					}
+				},
+			)()
+		}
+
+		os.Remove(msg.path)
+
+		if msg.entryId == "" {
+			return entryCreatedMsg{entry: entry}
+		}
+		return entrySavedMsg{entry: entry}
+	}
+}
@@ -0,0 +1,173 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorDB counts created and updated entries, tags could not be created
+// if tagsErr is set
+type editorDB struct {
+	db.Database
+	created, updated int
+	tagsErr          error
+}
+
+func (d *editorDB) CreateEntry(ctx context.Context, journalId, title, content string) (*kb.Entry, error) {
+	d.created++
+	return &kb.Entry{Id: "new", JournalId: journalId, Title: title, Content: content}, nil
+}
+
+func (d *editorDB) UpdateEntry(ctx context.Context, journalId, entryId, title, content string) (*kb.Entry, error) {
+	d.updated++
+	return &kb.Entry{Id: entryId, JournalId: journalId, Title: title, Content: content}, nil
+}
+
+func (d *editorDB) ListEntryTags(ctx context.Context, journalId, entryId string) ([]kb.Tag, error) {
+	return nil, nil
+}
+
+func (d *editorDB) CreateTags(ctx context.Context, labels []string) ([]kb.Tag, error) {
+	if d.tagsErr != nil {
+		return nil, d.tagsErr
+	}
+	tags := make([]kb.Tag, len(labels))
+	for i, label := range labels {
+		tags[i] = kb.Tag{Id: label, Label: label}
+	}
+	return tags, nil
+}
+
+func (d *editorDB) AssignTagsToEntry(ctx context.Context, journalId, entryId string, tagIds []string) error {
+	return nil
+}
+
+// editedEntryFile prepares file of new or existing entry and replaces its
+// content with data as if it was edited
+func editedEntryFile(t *testing.T, entryId, data string) editorFinishedMsg {
+	t.Helper()
+	msg, err := writeEntryFile(entryFrontMatter{Title: newEntryTitle, Tags: []string{}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(msg.path) })
+	msg.journalId, msg.entryId = "j1", entryId
+
+	if data != "" {
+		if err := os.WriteFile(msg.path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return editorFinishedMsg{editorReadyMsg: msg}
+}
+
+// collectMsgs runs commands of batch messages and returns all messages
+func collectMsgs(msg tea.Msg) []tea.Msg {
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, cmd := range batch {
+		if cmd != nil {
+			msgs = append(msgs, collectMsgs(cmd())...)
+		}
+	}
+	return msgs
+}
+
+func TestSaveEntryFile(t *testing.T) {
+	const edited = "---\ntitle: Day\ntags: [work]\n---\n\nText\n"
+
+	tests := []struct {
+		name     string
+		entryId  string
+		data     string // Edited file, empty if file is not changed
+		tagsErr  error
+		want     []string // Types of messages
+		err      string
+		created  int
+		updated  int
+		keepFile bool
+	}{
+		{name: "unchanged new entry", want: []string{"<nil>"}},
+		{name: "new entry", data: edited, want: []string{"tui.entryCreatedMsg"}, created: 1},
+		{name: "existing entry", entryId: "e1", data: edited, want: []string{"tui.entrySavedMsg"}, updated: 1},
+		{
+			name:     "empty title",
+			data:     "---\ntitle: ''\n---\n\nText\n",
+			want:     []string{"tui.errMsg"},
+			err:      "entry title could not be empty, changes are kept in",
+			keepFile: true,
+		},
+		{
+			name:     "tags of existing entry",
+			entryId:  "e1",
+			data:     edited,
+			tagsErr:  errors.New("disk is full"),
+			want:     []string{"tui.errMsg"},
+			err:      "disk is full, changes are kept in",
+			updated:  1,
+			keepFile: true,
+		},
+		{
+			name:     "tags of new entry",
+			data:     edited,
+			tagsErr:  errors.New("disk is full"),
+			want:     []string{"tui.entryCreatedMsg", "tui.errMsg"},
+			err:      "entry new is created without tags: disk is full, changes are kept in",
+			created:  1,
+			keepFile: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &editorDB{tagsErr: tt.tagsErr}
+			msg := editedEntryFile(t, tt.entryId, tt.data)
+
+			var got []string
+			var failed *errMsg
+			for _, m := range collectMsgs(saveEntryFile(context.Background(), database, msg)()) {
+				got = append(got, fmt.Sprintf("%T", m))
+				if e, ok := m.(errMsg); ok {
+					failed = &e
+				}
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("saveEntryFile() = %v, want %v", got, tt.want)
+			}
+			if tt.err != "" && (failed == nil || !strings.Contains(failed.Error(), tt.err)) {
+				t.Errorf("saveEntryFile() error = %v, want containing %q", failed, tt.err)
+			}
+			if database.created != tt.created || database.updated != tt.updated {
+				t.Errorf("created %d and updated %d entries, want %d and %d", database.created, database.updated, tt.created, tt.updated)
+			}
+			if _, err := os.Stat(msg.path); (err == nil) != tt.keepFile {
+				t.Errorf("file is kept = %v, want %v", err == nil, tt.keepFile)
+			}
+
+			// Retry saves file again as update of the entry
+			if failed != nil && failed.retry != nil {
+				database.tagsErr = nil
+				if _, ok := failed.retry().(entrySavedMsg); !ok {
+					t.Errorf("retry did not save entry")
+				}
+				if database.created != tt.created || database.updated != tt.updated+1 {
+					t.Errorf("retry created %d and updated %d entries, want %d and %d", database.created, database.updated, tt.created, tt.updated+1)
+				}
+			}
+		})
+	}
+}
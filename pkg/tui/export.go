@@ -0,0 +1,60 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/markdown"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type entryExportedMsg struct {
+	path string
+}
+
+// Write entry with front matter to Markdown file in dir, file name is built
+// from title and ID so repeated export overwrites the same file
+func exportEntry(ctx context.Context, database db.Database, journalId, entryId, dir string) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		operation := fmt.Sprintf("exportEntry(%s,%s)", journalId, entryId)
+
+		entry, err := database.GetEntryById(currentCtx, journalId, entryId)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+		if entry == nil {
+			return errMsg{operation: operation, err: db.ErrEntryNotFound}
+		}
+
+		tags, err := database.ListEntryTags(currentCtx, journalId, entryId)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+
+		data, err := markdown.FormatEntry(*entry, tags)
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+
+		path, err := filepath.Abs(filepath.Join(dir, markdown.EntryFileName(*entry)))
+		if err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return errMsg{operation: operation, err: err}
+		}
+
+		return entryExportedMsg{path: path}
+	}
+}
@@ -0,0 +1,114 @@
+//go:build tui
+
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// initHelp initializes help renderer for footer and help overlay
+func initHelp() help.Model {
+	h := help.New()
+	h.ShortSeparator = " | "
+	h.FullSeparator = "      "
+	h.Styles.ShortKey = helpKeyStyle
+	h.Styles.ShortDesc = helpDescStyle
+	h.Styles.ShortSeparator = helpDescStyle
+	h.Styles.FullKey = helpKeyStyle
+	h.Styles.FullDesc = helpDescStyle
+	h.Styles.FullSeparator = helpDescStyle
+
+	return h
+}
+
+// shortHelp returns bindings shown in footer for current focus state
+func (m model) shortHelp() []key.Binding {
+	var bindings []key.Binding
+	switch {
+	case m.showHelp:
+		return []key.Binding{m.keys.esc, m.keys.help}
+	case m.showErrorLog:
+		return []key.Binding{m.keys.esc}
+	case m.showPalette:
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "run")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close")),
+		}
+	case m.focusState == focusJournals:
+		bindings = []key.Binding{m.keys.quit, m.keys.enter}
+	case m.focusState == focusEntries && m.selectedJournalId != "":
+		bindings = []key.Binding{m.keys.esc, m.keys.enter, m.keys.editor, m.keys.mark}
+	case m.focusState == focusCalendar:
+		bindings = []key.Binding{m.keys.esc, m.keys.enter}
+	case m.focusState == focusEntry && m.viewer.Editing():
+		return []key.Binding{m.keys.esc, m.keys.save}
+	case m.focusState == focusEntry:
+		bindings = []key.Binding{m.keys.esc, m.keys.edit, m.keys.editor}
+	default:
+		bindings = []key.Binding{m.keys.quit}
+	}
+
+	if m.wideLayout() {
+		bindings = append(bindings, m.keys.nextPane)
+	}
+	if m.retry != nil {
+		bindings = append(bindings, m.keys.retry)
+	}
+
+	return append(bindings, m.keys.palette, m.keys.errorLog, m.keys.help)
+}
+
+// fullHelp returns groups of bindings shown in help overlay for current focus state
+func (m model) fullHelp() [][]key.Binding {
+	navigation := []key.Binding{m.keys.up, m.keys.down, m.keys.pageUp, m.keys.pageDown}
+
+	var actions []key.Binding
+	switch m.focusState {
+	case focusJournals:
+		navigation = append(navigation, m.keys.top, m.keys.bottom)
+		actions = []key.Binding{m.keys.enter, m.keys.yankId, m.keys.sortField, m.keys.sortOrder}
+	case focusEntries:
+		navigation = append(navigation, m.keys.top, m.keys.bottom)
+		actions = []key.Binding{
+			m.keys.enter, m.keys.esc, m.keys.editor, m.keys.yank, m.keys.yankId, m.keys.calendar, m.keys.sortField, m.keys.sortOrder,
+			m.keys.mark, m.keys.delete, m.keys.tag, m.keys.untag, m.keys.move,
+		}
+		if !m.wideLayout() {
+			actions = append(actions, m.keys.growList, m.keys.shrinkList)
+		}
+	case focusCalendar:
+		navigation = []key.Binding{
+			key.NewBinding(key.WithKeys("left", "right"), key.WithHelp("←/→", "prev/next day")),
+			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "prev/next week")),
+			key.NewBinding(key.WithKeys("pgup", "pgdown"), key.WithHelp("pgup/pgdown", "prev/next month")),
+			key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "today")),
+		}
+		actions = []key.Binding{m.keys.enter, m.keys.esc, m.keys.calendar}
+	case focusEntry:
+		actions = []key.Binding{m.keys.edit, m.keys.save, m.keys.restore, m.keys.editor, m.keys.yank, m.keys.yankId, m.keys.esc}
+	}
+
+	var general []key.Binding
+	if m.wideLayout() {
+		general = append(general, m.keys.nextPane, m.keys.prevPane)
+	}
+	general = append(general, m.keys.palette, m.keys.retry, m.keys.errorLog, m.keys.help)
+	if m.focusState == focusJournals {
+		general = append(general, m.keys.quit)
+	}
+	general = append(general, m.keys.forceQuit)
+
+	return [][]key.Binding{navigation, actions, general}
+}
+
+// helpOverlayView renders full screen help for current focus state
+func (m model) helpOverlayView() string {
+	title := helpTitleStyle.Render("Help: " + string(m.focusState))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		helpOverlayStyle.Render(m.help.FullHelpView(m.fullHelp())),
+	)
+}
@@ -0,0 +1,449 @@
+//go:build tui
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// keyAction is a name of action used in keymap config file
+type keyAction string
+
+const (
+	actionUp         keyAction = "up"
+	actionDown       keyAction = "down"
+	actionPageUp     keyAction = "page_up"
+	actionPageDown   keyAction = "page_down"
+	actionTop        keyAction = "top"
+	actionBottom     keyAction = "bottom"
+	actionSelect     keyAction = "select"
+	actionBack       keyAction = "back"
+	actionEdit       keyAction = "edit"
+	actionEditor     keyAction = "editor"
+	actionRetry      keyAction = "retry"
+	actionErrorLog   keyAction = "error_log"
+	actionGrowList   keyAction = "grow_list"
+	actionShrinkList keyAction = "shrink_list"
+	actionNextPane   keyAction = "next_pane"
+	actionPrevPane   keyAction = "prev_pane"
+	actionPalette    keyAction = "palette"
+	actionCalendar   keyAction = "calendar"
+	actionSortOrder  keyAction = "sort_order"
+	actionSortField  keyAction = "sort_field"
+	actionYank       keyAction = "yank"
+	actionYankId     keyAction = "yank_id"
+	actionSave       keyAction = "save"
+	actionRestore    keyAction = "restore_draft"
+	actionMark       keyAction = "mark"
+	actionDelete     keyAction = "delete"
+	actionTag        keyAction = "tag"
+	actionUntag      keyAction = "untag"
+	actionMove       keyAction = "move"
+	actionHelp       keyAction = "help"
+	actionQuit       keyAction = "quit"
+	actionForceQuit  keyAction = "force_quit"
+)
+
+// Help descriptions of actions
+var keyActionHelp = map[keyAction]string{
+	actionUp:         "up",
+	actionDown:       "down",
+	actionPageUp:     "prev page",
+	actionPageDown:   "next page",
+	actionTop:        "go to start",
+	actionBottom:     "go to end",
+	actionSelect:     "select",
+	actionBack:       "back",
+	actionEdit:       "edit",
+	actionEditor:     "open in $EDITOR",
+	actionRetry:      "retry",
+	actionErrorLog:   "error log",
+	actionGrowList:   "grow list",
+	actionShrinkList: "shrink list",
+	actionNextPane:   "next pane",
+	actionPrevPane:   "prev pane",
+	actionPalette:    "command palette",
+	actionCalendar:   "calendar",
+	actionSortOrder:  "reverse sort",
+	actionSortField:  "sort field",
+	actionYank:       "copy content",
+	actionYankId:     "copy ID",
+	actionSave:       "save",
+	actionRestore:    "restore draft",
+	actionMark:       "mark",
+	actionDelete:     "delete",
+	actionTag:        "tag",
+	actionUntag:      "untag",
+	actionMove:       "move",
+	actionHelp:       "help",
+	actionQuit:       "quit",
+	actionForceQuit:  "force quit",
+}
+
+type keyBindings = map[keyAction][]string
+
+// Built-in keymap presets, selected by preset name in keymap config file
+var keymapPresets = map[string]keyBindings{
+	"default": {
+		actionUp:         {"up", "k"},
+		actionDown:       {"down", "j"},
+		actionPageUp:     {"left", "h", "pgup", "b", "u"},
+		actionPageDown:   {"right", "l", "pgdown", "f", "d"},
+		actionTop:        {"home", "g"},
+		actionBottom:     {"end", "G"},
+		actionSelect:     {"enter"},
+		actionBack:       {"esc"},
+		actionEdit:       {"e"},
+		actionEditor:     {"E"},
+		actionRetry:      {"r"},
+		actionErrorLog:   {"L"},
+		actionGrowList:   {"+"},
+		actionShrinkList: {"-"},
+		actionNextPane:   {"tab"},
+		actionPrevPane:   {"shift+tab"},
+		actionPalette:    {"ctrl+p"},
+		actionCalendar:   {"c"},
+		actionSortOrder:  {"o"},
+		actionSortField:  {"s"},
+		actionYank:       {"y"},
+		actionYankId:     {"Y"},
+		actionSave:       {"ctrl+s"},
+		actionRestore:    {"R"},
+		actionMark:       {"space"},
+		actionDelete:     {"D"},
+		actionTag:        {"t"},
+		actionUntag:      {"T"},
+		actionMove:       {"m"},
+		actionHelp:       {"?"},
+		actionQuit:       {"q"},
+		actionForceQuit:  {"ctrl+c"},
+	},
+	"vim": {
+		actionUp:         {"k", "up"},
+		actionDown:       {"j", "down"},
+		actionPageUp:     {"ctrl+b", "pgup"},
+		actionPageDown:   {"ctrl+f", "pgdown"},
+		actionTop:        {"g", "home"},
+		actionBottom:     {"G", "end"},
+		actionSelect:     {"enter", "l"},
+		actionBack:       {"esc", "h"},
+		actionEdit:       {"i"},
+		actionEditor:     {"E"},
+		actionRetry:      {"r"},
+		actionErrorLog:   {"L"},
+		actionGrowList:   {"+"},
+		actionShrinkList: {"-"},
+		actionNextPane:   {"tab"},
+		actionPrevPane:   {"shift+tab"},
+		actionPalette:    {"ctrl+p"},
+		actionCalendar:   {"c"},
+		actionSortOrder:  {"o"},
+		actionSortField:  {"s"},
+		actionYank:       {"y"},
+		actionYankId:     {"Y"},
+		actionSave:       {"ctrl+s"},
+		actionRestore:    {"R"},
+		actionMark:       {"space"},
+		actionDelete:     {"D"},
+		actionTag:        {"t"},
+		actionUntag:      {"T"},
+		actionMove:       {"m"},
+		actionHelp:       {"?"},
+		actionQuit:       {"q"},
+		actionForceQuit:  {"ctrl+c"},
+	},
+	"emacs": {
+		actionUp:         {"ctrl+p", "up"},
+		actionDown:       {"ctrl+n", "down"},
+		actionPageUp:     {"alt+v", "pgup"},
+		actionPageDown:   {"ctrl+v", "pgdown"},
+		actionTop:        {"alt+<", "home"},
+		actionBottom:     {"alt+>", "end"},
+		actionSelect:     {"enter"},
+		actionBack:       {"esc", "ctrl+g"},
+		actionEdit:       {"ctrl+o"},
+		actionEditor:     {"ctrl+x"},
+		actionRetry:      {"ctrl+r"},
+		actionErrorLog:   {"ctrl+l"},
+		actionGrowList:   {"+"},
+		actionShrinkList: {"-"},
+		actionNextPane:   {"tab"},
+		actionPrevPane:   {"shift+tab"},
+		actionPalette:    {"alt+x"},
+		actionCalendar:   {"alt+c"},
+		actionSortOrder:  {"alt+o"},
+		actionSortField:  {"alt+s"},
+		actionYank:       {"alt+w"},
+		actionYankId:     {"alt+W"},
+		actionSave:       {"ctrl+s"},
+		actionRestore:    {"alt+r"},
+		actionMark:       {"ctrl+@"},
+		actionDelete:     {"ctrl+d"},
+		actionTag:        {"alt+t"},
+		actionUntag:      {"alt+T"},
+		actionMove:       {"alt+m"},
+		actionHelp:       {"f1", "?"},
+		actionQuit:       {"q"},
+		actionForceQuit:  {"ctrl+c"},
+	},
+}
+
+const defaultKeymapPreset = "default"
+
+// keymapFile is a structure of keymap config file, keys override bindings of preset
+type keymapFile struct {
+	Preset string              `toml:"preset"`
+	Keys   map[string][]string `toml:"keys"`
+}
+
+// Keymap of the TUI
+type keymap struct {
+	up       key.Binding
+	down     key.Binding
+	pageUp   key.Binding
+	pageDown key.Binding
+	top      key.Binding
+	bottom   key.Binding
+
+	enter      key.Binding
+	esc        key.Binding
+	edit       key.Binding
+	editor     key.Binding
+	retry      key.Binding
+	errorLog   key.Binding
+	growList   key.Binding
+	shrinkList key.Binding
+	nextPane   key.Binding
+	prevPane   key.Binding
+	palette    key.Binding
+	calendar   key.Binding
+	sortOrder  key.Binding
+	sortField  key.Binding
+	yank       key.Binding
+	yankId     key.Binding
+	save       key.Binding
+	restore    key.Binding
+	mark       key.Binding
+	delete     key.Binding
+	tag        key.Binding
+	untag      key.Binding
+	move       key.Binding
+	help       key.Binding
+	quit       key.Binding
+	forceQuit  key.Binding
+}
+
+func newBinding(bindings keyBindings, action keyAction) key.Binding {
+	keys := bindings[action]
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+
+	// Space is named in keymap, but key messages carry its character
+	matched := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" {
+			k = " "
+		}
+		matched[i] = k
+	}
+
+	return key.NewBinding(
+		key.WithKeys(matched...),
+		key.WithHelp(keys[0], keyActionHelp[action]),
+	)
+}
+
+// initKeymap builds keymap from action bindings
+func initKeymap(bindings keyBindings) keymap {
+	return keymap{
+		up:       newBinding(bindings, actionUp),
+		down:     newBinding(bindings, actionDown),
+		pageUp:   newBinding(bindings, actionPageUp),
+		pageDown: newBinding(bindings, actionPageDown),
+		top:      newBinding(bindings, actionTop),
+		bottom:   newBinding(bindings, actionBottom),
+
+		enter:      newBinding(bindings, actionSelect),
+		esc:        newBinding(bindings, actionBack),
+		edit:       newBinding(bindings, actionEdit),
+		editor:     newBinding(bindings, actionEditor),
+		retry:      newBinding(bindings, actionRetry),
+		errorLog:   newBinding(bindings, actionErrorLog),
+		growList:   newBinding(bindings, actionGrowList),
+		shrinkList: newBinding(bindings, actionShrinkList),
+		nextPane:   newBinding(bindings, actionNextPane),
+		prevPane:   newBinding(bindings, actionPrevPane),
+		palette:    newBinding(bindings, actionPalette),
+		calendar:   newBinding(bindings, actionCalendar),
+		sortOrder:  newBinding(bindings, actionSortOrder),
+		sortField:  newBinding(bindings, actionSortField),
+		yank:       newBinding(bindings, actionYank),
+		yankId:     newBinding(bindings, actionYankId),
+		save:       newBinding(bindings, actionSave),
+		restore:    newBinding(bindings, actionRestore),
+		mark:       newBinding(bindings, actionMark),
+		delete:     newBinding(bindings, actionDelete),
+		tag:        newBinding(bindings, actionTag),
+		untag:      newBinding(bindings, actionUntag),
+		move:       newBinding(bindings, actionMove),
+		help:       newBinding(bindings, actionHelp),
+		quit:       newBinding(bindings, actionQuit),
+		forceQuit:  newBinding(bindings, actionForceQuit),
+	}
+}
+
+// terminalKeyAliases are keys terminals send as the same byte as editing
+// keys, so binding them would also trigger on the editing key (ctrl+h is
+// backspace in many terminals) or never trigger at all
+var terminalKeyAliases = map[string]string{
+	"ctrl+h": "backspace",
+	"ctrl+i": "tab",
+	"ctrl+m": "enter",
+	"ctrl+[": "esc",
+}
+
+// checkKeymapConflicts returns error listing keys bound to more than one
+// action and keys which terminals do not tell apart from editing keys
+func checkKeymapConflicts(bindings keyBindings) error {
+	owners := make(map[string][]string)
+	for action, keys := range bindings {
+		for _, k := range keys {
+			owners[k] = append(owners[k], string(action))
+		}
+	}
+
+	var conflicts []string
+	for k, actions := range owners {
+		if alias, ok := terminalKeyAliases[k]; ok {
+			sort.Strings(actions)
+			conflicts = append(conflicts, fmt.Sprintf("%q of %s is sent as %s by terminals", k, strings.Join(actions, ", "), alias))
+			continue
+		}
+		if len(actions) > 1 {
+			sort.Strings(actions)
+			conflicts = append(conflicts, fmt.Sprintf("%q is bound to %s", k, strings.Join(actions, ", ")))
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	return fmt.Errorf("keymap conflicts: %s", strings.Join(conflicts, "; "))
+}
+
+// keymapFilePath returns configured path to keymap config file or default
+// location in user config directory
+func keymapFilePath(path string) string {
+	if path != "" {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "firn", "keymap.toml")
+}
+
+// loadKeymap loads keymap from config file on top of selected preset
+// and checks it for conflicts. Default preset is used if there is no file.
+func loadKeymap(file string) (keymap, error) {
+	var cfg keymapFile
+
+	path := keymapFilePath(file)
+	if path != "" {
+		meta, err := toml.DecodeFile(path, &cfg)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return keymap{}, fmt.Errorf("failed to load keymap file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return keymap{}, fmt.Errorf("invalid keymap file %s: unknown field %q", path, undecoded[0].String())
+		}
+	}
+
+	bindings, err := resolveKeyBindings(cfg)
+	if err != nil {
+		return keymap{}, fmt.Errorf("invalid keymap file %s: %w", path, err)
+	}
+
+	if err := checkKeymapConflicts(bindings); err != nil {
+		return keymap{}, fmt.Errorf("invalid keymap file %s: %w", path, err)
+	}
+
+	return initKeymap(bindings), nil
+}
+
+// resolveKeyBindings applies overrides from keymap config to its preset
+func resolveKeyBindings(cfg keymapFile) (keyBindings, error) {
+	presetName := cfg.Preset
+	if presetName == "" {
+		presetName = defaultKeymapPreset
+	}
+
+	preset, ok := keymapPresets[presetName]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, must be one of default, vim, emacs", presetName)
+	}
+
+	bindings := make(keyBindings, len(preset))
+	for action, keys := range preset {
+		bindings[action] = keys
+	}
+
+	for name, keys := range cfg.Keys {
+		action := keyAction(name)
+		if _, ok := keyActionHelp[action]; !ok {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+		bindings[action] = keys
+	}
+
+	return bindings, nil
+}
+
+// listKeyMap returns navigation keymap for lists, list help and quit
+// bindings are disabled as they are handled by the model
+func (k keymap) listKeyMap() list.KeyMap {
+	km := list.DefaultKeyMap()
+
+	km.CursorUp = k.up
+	km.CursorDown = k.down
+	km.PrevPage = k.pageUp
+	km.NextPage = k.pageDown
+	km.GoToStart = k.top
+	km.GoToEnd = k.bottom
+
+	km.ShowFullHelp = key.NewBinding(key.WithDisabled())
+	km.CloseFullHelp = key.NewBinding(key.WithDisabled())
+	km.Quit = key.NewBinding(key.WithDisabled())
+	km.ForceQuit = key.NewBinding(key.WithDisabled())
+
+	return km
+}
+
+// viewportKeyMap returns scrolling keymap for viewports
+func (k keymap) viewportKeyMap() viewport.KeyMap {
+	km := viewport.DefaultKeyMap()
+
+	km.Up = k.up
+	km.Down = k.down
+	km.PageUp = k.pageUp
+	km.PageDown = k.pageDown
+	km.Left = key.NewBinding(key.WithDisabled())
+	km.Right = key.NewBinding(key.WithDisabled())
+
+	return km
+}
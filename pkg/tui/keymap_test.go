@@ -0,0 +1,81 @@
+//go:build tui
+
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckKeymapConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  keymapFile
+		err  string
+	}{
+		{name: "default preset", cfg: keymapFile{}},
+		{name: "vim preset", cfg: keymapFile{Preset: "vim"}},
+		{name: "emacs preset", cfg: keymapFile{Preset: "emacs"}},
+		{
+			name: "rebound key",
+			cfg:  keymapFile{Keys: map[string][]string{"edit": {"i"}, "calendar": {"e"}}},
+		},
+		{
+			name: "unbound action",
+			cfg:  keymapFile{Keys: map[string][]string{"quit": {}}},
+		},
+		{
+			name: "key of another action",
+			cfg:  keymapFile{Keys: map[string][]string{"quit": {"q", "e"}}},
+			err:  `keymap conflicts: "e" is bound to edit, quit`,
+		},
+		{
+			name: "key of preset action",
+			cfg:  keymapFile{Preset: "vim", Keys: map[string][]string{"help": {"l"}}},
+			err:  `"l" is bound to help, select`,
+		},
+		{
+			name: "several conflicts",
+			cfg:  keymapFile{Keys: map[string][]string{"yank": {"q", "r"}}},
+			err:  `keymap conflicts: "q" is bound to quit, yank; "r" is bound to retry, yank`,
+		},
+		{
+			name: "key sent as backspace",
+			cfg:  keymapFile{Preset: "emacs", Keys: map[string][]string{"help": {"ctrl+h"}}},
+			err:  `keymap conflicts: "ctrl+h" of help is sent as backspace by terminals`,
+		},
+		{
+			name: "key sent as enter",
+			cfg:  keymapFile{Keys: map[string][]string{"select": {"enter", "ctrl+m"}}},
+			err:  `"ctrl+m" of select is sent as enter by terminals`,
+		},
+		{
+			name: "unknown action",
+			cfg:  keymapFile{Keys: map[string][]string{"jump": {"J"}}},
+			err:  `unknown action "jump"`,
+		},
+		{
+			name: "unknown preset",
+			cfg:  keymapFile{Preset: "nano"},
+			err:  `unknown preset "nano"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindings, err := resolveKeyBindings(tt.cfg)
+			if err == nil {
+				err = checkKeymapConflicts(bindings)
+			}
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("checkKeymapConflicts() error = %v, want containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkKeymapConflicts() error = %v", err)
+			}
+		})
+	}
+}
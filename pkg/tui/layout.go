@@ -0,0 +1,104 @@
+//go:build tui
+
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Minimal terminal width to show journals, entries and viewer side by side
+const wideLayoutMinWidth = 160
+
+// Shares of journals and entries columns in wide layout, in percents,
+// viewer takes the rest
+const (
+	journalsColumnRatio = 25
+	entriesColumnRatio  = 35
+)
+
+// wideLayout reports whether panes are shown as three columns
+func (m model) wideLayout() bool {
+	return m.width >= wideLayoutMinWidth
+}
+
+// entriesShown reports whether entries list and viewer are visible
+func (m model) entriesShown() bool {
+	if !m.entriesViewActive() {
+		return false
+	}
+	return m.wideLayout() || m.focusState != focusJournals
+}
+
+// columnWidths returns widths of journals, entries and viewer columns, each
+// pane takes full width in stacked layout
+func (m model) columnWidths() (int, int, int) {
+	if !m.wideLayout() {
+		return m.width, m.width, m.width
+	}
+
+	// Two columns are taken by dividers
+	available := m.width - 2
+	journalsWidth := available * journalsColumnRatio / 100
+	entriesWidth := available * entriesColumnRatio / 100
+
+	return journalsWidth, entriesWidth, available - journalsWidth - entriesWidth
+}
+
+// focusOrder returns focus states of columns available for tab navigation
+func (m model) focusOrder() []focusState {
+	order := []focusState{focusJournals}
+	if m.entriesViewActive() && m.showCalendar {
+		order = append(order, focusCalendar)
+	}
+	if m.entriesViewActive() {
+		order = append(order, focusEntries)
+	}
+	if m.entrySelected() {
+		order = append(order, focusEntry)
+	}
+	return order
+}
+
+// cycleFocus moves focus to the next (or previous when step is negative) column
+func (m *model) cycleFocus(step int) {
+	order := m.focusOrder()
+	current := 0
+	for i, state := range order {
+		if state == m.focusState {
+			current = i
+			break
+		}
+	}
+
+	next := (current + step + len(order)) % len(order)
+	m.setFocusState(order[next])
+	m.resizeComponents()
+}
+
+// wideContentView renders journals, entries and viewer as three columns
+func (m model) wideContentView() string {
+	journalsWidth, entriesWidth, viewerWidth := m.columnWidths()
+
+	column := func(view string, width int) string {
+		return lipgloss.NewStyle().
+			Width(width).
+			MaxWidth(width).
+			Height(m.contentHeight).
+			MaxHeight(m.contentHeight).
+			Render(view)
+	}
+
+	divider := splitDividerStyle.Render(
+		strings.TrimSuffix(strings.Repeat("│\n", m.contentHeight), "\n"),
+	)
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		column(m.journals.View(), journalsWidth),
+		divider,
+		column(m.entriesColumnView(), entriesWidth),
+		divider,
+		column(m.viewer.View(), viewerWidth),
+	)
+}
@@ -4,34 +4,217 @@ package tui
 
 import (
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/kompotkot/firn/pkg/kb"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// initList initializes a list of items
-func initList(title string) list.Model {
-	// List item styles
+// newDelegate creates list item delegate with theme styles
+func newDelegate() list.DefaultDelegate {
 	ld := list.NewDefaultDelegate()
 	ld.Styles.SelectedTitle = listSelectedTitleStyle
 	ld.Styles.SelectedDesc = listSelectedDescStyle
+	ld.Styles.NormalTitle = listNormalTitleStyle
+	ld.Styles.NormalDesc = listNormalDescStyle
 
+	return ld
+}
+
+// initList initializes a list of items
+func initList(title string) list.Model {
 	// Initialize list (dimensions will be set when window size is received)
-	l := list.New([]list.Item{}, ld, 0, 0)
+	l := list.New([]list.Item{}, newDelegate(), 0, 0)
 	l.Title = title
 	l.SetFilteringEnabled(false)
 	l.SetShowPagination(true)
+	l.SetShowHelp(false) // Help is shown in footer and help overlay
 	l.Paginator.Type = paginator.Arabic
 	l.Styles.Title = listTitleStyle
 	l.Styles.NoItems = listNoItemsStyle
+	l.Styles.StatusBar = listStatusBarStyle
+	l.Styles.PaginationStyle = listPaginationStyle
 
 	return l
 }
 
+// Rows taken by one item of default delegate: title, description and spacing
+const listItemRows = 3
+
+// listItemAt returns global index of the list item rendered at row y of the
+// list view, rows are counted from the top of the list
+func listItemAt(l list.Model, y int) (int, bool) {
+	if y < 0 {
+		return 0, false
+	}
+
+	// Skip title and status bar rendered above the items
+	y -= lipgloss.Height(l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)))
+	if l.ShowStatusBar() {
+		y -= lipgloss.Height(l.Styles.StatusBar.Render(""))
+	}
+	if y < 0 {
+		return 0, false
+	}
+
+	row := y / listItemRows
+	if row >= l.Paginator.ItemsOnPage(len(l.VisibleItems())) {
+		return 0, false
+	}
+
+	return l.Paginator.Page*l.Paginator.PerPage + row, true
+}
+
+// Symbol prepended to title of marked entry
+const markSymbol = "● "
+
+// markDelegate renders entries marked for bulk actions with a symbol
+type markDelegate struct {
+	list.DefaultDelegate
+	marked map[string]bool // Shared with entries pane, keyed by entry ID
+}
+
+func (d markDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if ei, ok := item.(eItem); ok && d.marked[ei.entry.Id] {
+		ei.widthTitle -= lipgloss.Width(markSymbol)
+		item = markedEItem{ei}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// markedEItem is an entry item marked for bulk actions
+type markedEItem struct {
+	eItem
+}
+
+func (i markedEItem) Title() string {
+	return markSymbol + i.eItem.Title()
+}
+
+// initSpinner initializes a spinner shown in list footer while next page is loading
+func initSpinner() spinner.Model {
+	return spinner.New(
+		spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(listLoadingStyle),
+	)
+}
+
+// Minimal number of loaded items left below the cursor before next page is requested
+const loadMoreThreshold = 10
+
+// pageLoader tracks lazy loading of list pages from the database
+type pageLoader struct {
+	limit   int  // Number of items requested per page
+	offset  int  // Offset of the next page
+	loading bool // Next page request is in flight
+	hasMore bool // Last page was full, so more items may exist
+}
+
+func newPageLoader(limit int) pageLoader {
+	return pageLoader{limit: limit, hasMore: true}
+}
+
+// needsMore reports whether next page should be requested for cursor at index
+func (p pageLoader) needsMore(index, total, perPage int) bool {
+	threshold := loadMoreThreshold
+	if perPage > threshold {
+		threshold = perPage
+	}
+	return p.hasMore && !p.loading && index >= total-threshold
+}
+
+// accepts reports whether a page loaded at offset is the one being waited for
+func (p pageLoader) accepts(offset int) bool {
+	return offset == p.offset
+}
+
+// loaded records a page of count items received at offset
+func (p *pageLoader) loaded(offset, count int) {
+	p.loading = false
+	p.offset = offset + count
+	p.hasMore = count >= p.limit
+}
+
+// shift moves offset of the next page after items were added or removed
+// outside of page loading
+func (p *pageLoader) shift(n int) {
+	p.offset += n
+}
+
+func (p *pageLoader) reset() {
+	*p = newPageLoader(p.limit)
+}
+
+// refreshed records count items reloaded from the start of the list when
+// requested number of items was asked
+func (p *pageLoader) refreshed(count, requested int) {
+	p.offset = count
+	p.hasMore = count >= requested
+}
+
+// itemVersion returns ID and update time of journal or entry item
+func itemVersion(it list.Item) (string, time.Time) {
+	switch i := it.(type) {
+	case jItem:
+		return i.journal.Id, i.journal.UpdatedAt
+	case eItem:
+		return i.entry.Id, i.entry.UpdatedAt
+	}
+	return "", time.Time{}
+}
+
+// sameItems reports whether lists have the same items in the same order
+// and none of them was updated
+func sameItems(a, b []list.Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		aId, aUpdated := itemVersion(a[i])
+		bId, bUpdated := itemVersion(b[i])
+		if aId != bId || !aUpdated.Equal(bUpdated) {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceItems sets items of the list keeping selection on the item with
+// the same ID. If the item is gone, the first remaining item after it is
+// selected, cursor stays at its position if there is none.
+func replaceItems(l *list.Model, items []list.Item) {
+	index := l.Index()
+	previous := l.Items()
+
+	positions := make(map[string]int, len(items))
+	for i, it := range items {
+		id, _ := itemVersion(it)
+		positions[id] = i
+	}
+
+	if index < len(previous) {
+		for _, it := range previous[index:] {
+			id, _ := itemVersion(it)
+			if i, ok := positions[id]; ok {
+				index = i
+				break
+			}
+		}
+	}
+
+	l.SetItems(items)
+	if len(items) > 0 {
+		l.Select(min(index, len(items)-1))
+	}
+}
+
 // Journal item
 
 // jItem represents a journal item in the list
@@ -42,6 +225,15 @@ type jItem struct {
 	widthDesc  int // Width for Description
 }
 
+// newJItem creates journal item fitted to list width
+func newJItem(j kb.Journal, width int) jItem {
+	return jItem{
+		journal:    j,
+		widthTitle: width - len(j.Name) - magicWidthPaddingNum,
+		widthDesc:  width - len(fmt.Sprintf("ID: %s", j.Id)) - magicWidthPaddingNum, // Available width for Description (full width minus ID length)
+	}
+}
+
 func (i jItem) Title() string {
 	name := lipgloss.NewStyle().Render(i.journal.Name)
 
@@ -73,6 +265,15 @@ type eItem struct {
 	widthDesc  int // Width for Description
 }
 
+// newEItem creates entry item fitted to list width
+func newEItem(e kb.Entry, width int) eItem {
+	return eItem{
+		entry:      e,
+		widthTitle: width - len(e.Title) - magicWidthPaddingNum,
+		widthDesc:  width - len(fmt.Sprintf("ID: %s", e.Id)) - magicWidthPaddingNum,
+	}
+}
+
 func (i eItem) Title() string {
 	title := lipgloss.NewStyle().Render(i.entry.Title)
 	width := i.widthTitle
@@ -104,7 +305,18 @@ func initTextarea() textarea.Model {
 	ta.SetWidth(80)  // Will be updated on window resize
 	ta.SetHeight(5)  // Will be updated on window resize
 	ta.ShowLineNumbers = false
+	ta.FocusedStyle.Prompt = viewerPromptStyle
+	ta.FocusedStyle.Text = viewerTextStyle
+	ta.FocusedStyle.Placeholder = viewerPlaceholderStyle
+	ta.BlurredStyle.Prompt = viewerPromptStyle
+	ta.BlurredStyle.Text = viewerTextStyle
+	ta.BlurredStyle.Placeholder = viewerPlaceholderStyle
 	ta.Blur() // Blur textarea so it doesn't intercept keyboard input
 
 	return ta
 }
+
+// initViewport initializes a viewport for Markdown rendered entry content
+func initViewport() viewport.Model {
+	return viewport.New(80, 5) // Will be updated on window resize
+}
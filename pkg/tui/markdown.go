@@ -0,0 +1,50 @@
+//go:build tui
+
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+)
+
+// markdownRenderer renders entry content as Markdown for the read mode of viewer
+type markdownRenderer struct {
+	style    string
+	width    int
+	renderer *glamour.TermRenderer
+}
+
+// newMarkdownRenderer creates renderer with glamour style of the active theme
+func newMarkdownRenderer() markdownRenderer {
+	style := markdownStyle
+	if style == "" {
+		style = styles.NoTTYStyle
+	}
+
+	return markdownRenderer{style: style}
+}
+
+// Render renders content wrapped to width, raw content is returned if
+// rendering fails
+func (r *markdownRenderer) Render(content string, width int) string {
+	if r.renderer == nil || r.width != width {
+		renderer, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(r.style),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			return content
+		}
+		r.renderer = renderer
+		r.width = width
+	}
+
+	out, err := r.renderer.Render(content)
+	if err != nil {
+		return content
+	}
+
+	return strings.TrimRight(out, "\n")
+}
@@ -0,0 +1,214 @@
+//go:build tui
+
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Lines scrolled by one mouse wheel step in the viewer
+const mouseWheelLines = 3
+
+// handleMouse routes mouse events to the pane under the pointer
+func (m *model) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if !m.ready || m.showHelp || m.showPalette {
+		return nil
+	}
+
+	if m.showErrorLog {
+		var cmd tea.Cmd
+		m.errorLog, cmd = m.errorLog.Update(msg)
+		return cmd
+	}
+
+	// Keep mouse away from lists while entry is edited
+	if m.viewer.Editing() {
+		return nil
+	}
+
+	// Rows are counted from the top of the content area
+	y := msg.Y - m.headerHeight
+	if m.draggingSplit {
+		return m.dragSplit(msg, y)
+	}
+	if y < 0 || y >= m.contentHeight {
+		return nil
+	}
+
+	if m.wideLayout() {
+		return m.handleColumnsMouse(msg, y)
+	}
+
+	switch {
+	case !m.entriesShown():
+		return m.handleJournalsMouse(msg, y, true)
+	case m.viewerFull:
+		m.handleViewerMouse(msg)
+		return nil
+	}
+
+	listHeight, _ := m.splitHeights()
+	switch {
+	case y < listHeight:
+		return m.handleEntriesColumnMouse(msg, msg.X, y)
+	case y == listHeight:
+		// Divider between entries list and viewer
+		if isLeftClick(msg) {
+			m.draggingSplit = true
+		}
+		return nil
+	default:
+		if isLeftClick(msg) {
+			if cmd, ok := m.openSelectedEntry(); ok {
+				return cmd
+			}
+		}
+		m.handleViewerMouse(msg)
+		return nil
+	}
+}
+
+// handleColumnsMouse routes mouse events in wide layout by column under the
+// pointer, click in not focused column moves focus to it
+func (m *model) handleColumnsMouse(msg tea.MouseMsg, y int) tea.Cmd {
+	journalsWidth, entriesWidth, _ := m.columnWidths()
+
+	// Columns are separated by one line dividers
+	switch {
+	case msg.X < journalsWidth:
+		focused := m.focusState == focusJournals
+		if isLeftClick(msg) && !focused {
+			m.setFocusState(focusJournals)
+		}
+		return m.handleJournalsMouse(msg, y, focused)
+	case msg.X > journalsWidth && msg.X <= journalsWidth+entriesWidth:
+		if !m.entriesViewActive() {
+			return nil
+		}
+		return m.handleEntriesColumnMouse(msg, msg.X-journalsWidth-1, y)
+	case msg.X > journalsWidth+entriesWidth+1:
+		if isLeftClick(msg) && m.entrySelected() {
+			m.setFocusState(focusEntry)
+		}
+		m.handleViewerMouse(msg)
+	}
+
+	return nil
+}
+
+// handleEntriesColumnMouse routes mouse events to calendar above entries
+// list when it is shown, click in not focused pane moves focus to it
+func (m *model) handleEntriesColumnMouse(msg tea.MouseMsg, x, y int) tea.Cmd {
+	if m.showCalendar {
+		if y < calendarHeight {
+			if !isLeftClick(msg) {
+				return nil
+			}
+			m.setFocusState(focusCalendar)
+			if day, ok := m.calendar.DayAt(x, y); ok {
+				return m.selectDay(day)
+			}
+			return nil
+		}
+		y -= calendarHeight
+	}
+
+	focused := m.focusState == focusEntries
+	if isLeftClick(msg) && !focused {
+		m.setFocusState(focusEntries)
+	}
+	return m.handleEntriesMouse(msg, y, focused)
+}
+
+// dragSplit resizes split while divider is dragged with left button
+func (m *model) dragSplit(msg tea.MouseMsg, y int) tea.Cmd {
+	if msg.Action == tea.MouseActionRelease {
+		m.draggingSplit = false
+		return nil
+	}
+	if m.contentHeight > 0 {
+		m.setSplitRatio(y * 100 / m.contentHeight)
+	}
+	return nil
+}
+
+// handleJournalsMouse selects journal on click, opens it on click of already
+// selected one when open is set and moves cursor on wheel
+func (m *model) handleJournalsMouse(msg tea.MouseMsg, y int, open bool) tea.Cmd {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.journals.CursorUp()
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.journals.CursorDown()
+	case isLeftClick(msg):
+		index, ok := m.journals.ItemAt(y)
+		if !ok {
+			return nil
+		}
+		if index == m.journals.GlobalIndex() {
+			if !open {
+				return nil
+			}
+			cmd, _ := m.openSelectedJournal()
+			return cmd
+		}
+		m.journals.Select(index)
+	default:
+		return nil
+	}
+
+	if m.journals.NeedsMore() {
+		return m.loadMoreJournals()
+	}
+	return nil
+}
+
+// handleEntriesMouse selects entry on click, opens it on click of already
+// selected one when open is set and moves cursor on wheel
+func (m *model) handleEntriesMouse(msg tea.MouseMsg, y int, open bool) tea.Cmd {
+	previousEntryId := m.selectedEntryId
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.entries.CursorUp()
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.entries.CursorDown()
+	case isLeftClick(msg):
+		index, ok := m.entries.ItemAt(y)
+		if !ok {
+			return nil
+		}
+		if index == m.entries.GlobalIndex() {
+			if !open {
+				return nil
+			}
+			cmd, _ := m.openSelectedEntry()
+			return cmd
+		}
+		m.entries.Select(index)
+	default:
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	if m.entries.NeedsMore() {
+		cmds = append(cmds, m.loadMoreEntries())
+	}
+	cmds = append(cmds, m.syncSelectedEntry(previousEntryId))
+
+	return tea.Batch(cmds...)
+}
+
+// handleViewerMouse scrolls entry content on wheel
+func (m *model) handleViewerMouse(msg tea.MouseMsg) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.viewer.Scroll(-mouseWheelLines)
+	case tea.MouseButtonWheelDown:
+		m.viewer.Scroll(mouseWheelLines)
+	}
+}
+
+func isLeftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
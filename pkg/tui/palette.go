@@ -0,0 +1,329 @@
+//go:build tui
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// paletteCommand is an action listed in command palette
+type paletteCommand struct {
+	title   string
+	binding key.Binding // Key of the same action shown as a hint, could be disabled
+	run     func(m *model) tea.Cmd
+}
+
+// paletteSource adapts commands to fuzzy matching by title
+type paletteSource []paletteCommand
+
+func (s paletteSource) String(i int) string { return s[i].title }
+func (s paletteSource) Len() int            { return len(s) }
+
+// commandPalette lists commands fuzzy matched by typed text
+type commandPalette struct {
+	title    string
+	input    textinput.Model
+	commands []paletteCommand
+	matches  fuzzy.Matches
+	cursor   int
+	height   int
+}
+
+func newCommandPalette() commandPalette {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Type a command"
+	ti.PromptStyle = viewerPromptStyle
+	ti.TextStyle = viewerTextStyle
+	ti.PlaceholderStyle = viewerPlaceholderStyle
+
+	return commandPalette{input: ti}
+}
+
+// Open resets input and lists given commands under title
+func (p *commandPalette) Open(title string, commands []paletteCommand) {
+	p.title = title
+	p.commands = commands
+	p.input.SetValue("")
+	p.input.Focus()
+	p.filter()
+}
+
+func (p *commandPalette) Close() {
+	p.input.Blur()
+	p.commands = nil
+	p.matches = nil
+}
+
+// Update moves cursor with arrow keys, other keys are typed into input
+func (p commandPalette) Update(msg tea.KeyMsg) (commandPalette, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyCtrlK:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return p, nil
+	case tea.KeyDown, tea.KeyCtrlJ:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return p, nil
+	}
+
+	value := p.input.Value()
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != value {
+		p.filter()
+	}
+
+	return p, cmd
+}
+
+// filter matches commands against input, all commands are listed in
+// original order for empty input
+func (p *commandPalette) filter() {
+	p.cursor = 0
+
+	pattern := strings.TrimSpace(p.input.Value())
+	if pattern != "" {
+		p.matches = fuzzy.FindFrom(pattern, paletteSource(p.commands))
+		return
+	}
+
+	p.matches = make(fuzzy.Matches, len(p.commands))
+	for i, c := range p.commands {
+		p.matches[i] = fuzzy.Match{Str: c.title, Index: i}
+	}
+}
+
+// Selected returns command under the cursor
+func (p commandPalette) Selected() (paletteCommand, bool) {
+	if p.cursor >= len(p.matches) {
+		return paletteCommand{}, false
+	}
+	return p.commands[p.matches[p.cursor].Index], true
+}
+
+func (p *commandPalette) SetSize(width, height int) {
+	p.input.Width = max(width-6, 1) // Padding and prompt
+	p.height = height
+}
+
+func (p commandPalette) View() string {
+	// Title with bottom padding, input line and empty line below it
+	rows := max(p.height-4, 1)
+
+	// Keep cursor visible when there are more matches than rows
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	end := min(start+rows, len(p.matches))
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		lines = append(lines, p.itemView(p.matches[i], i == p.cursor))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, paletteItemStyle.Render(listNoItemsStyle.Render("No matching commands")))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		paletteTitleStyle.Render(p.title),
+		paletteInputStyle.Render(p.input.View()),
+		"",
+		strings.Join(lines, "\n"),
+	)
+}
+
+// itemView renders command title with matched characters highlighted
+func (p commandPalette) itemView(match fuzzy.Match, selected bool) string {
+	command := p.commands[match.Index]
+
+	matched := make(map[int]bool, len(match.MatchedIndexes))
+	for _, i := range match.MatchedIndexes {
+		matched[i] = true
+	}
+
+	var sb strings.Builder
+	for i, r := range command.title {
+		if matched[i] {
+			sb.WriteString(paletteMatchStyle.Render(string(r)))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	if command.binding.Enabled() {
+		sb.WriteString(helpDescStyle.Render(fmt.Sprintf("  %s", command.binding.Help().Key)))
+	}
+
+	if selected {
+		return paletteSelectedStyle.Render(sb.String())
+	}
+	return paletteItemStyle.Render(sb.String())
+}
+
+// paletteCommands returns commands available in current focus state
+func (m model) paletteCommands() []paletteCommand {
+	var commands []paletteCommand
+
+	switch m.focusState {
+	case focusJournals:
+		commands = append(commands,
+			paletteCommand{title: "Open journal", binding: m.keys.enter, run: func(m *model) tea.Cmd {
+				cmd, _ := m.openSelectedJournal()
+				return cmd
+			}},
+			paletteCommand{title: "Copy journal ID", binding: m.keys.yankId, run: (*model).yankSelectedId},
+			paletteCommand{title: "Reverse journals sort order", binding: m.keys.sortOrder, run: func(m *model) tea.Cmd {
+				return m.changeJournalsSort(m.journalsSort.reversed())
+			}},
+			paletteCommand{title: "Sort journals by " + m.journalsSort.nextField().fieldLabel("name"), binding: m.keys.sortField, run: func(m *model) tea.Cmd {
+				return m.changeJournalsSort(m.journalsSort.nextField())
+			}},
+		)
+	case focusEntries:
+		commands = append(commands,
+			paletteCommand{title: "New entry", run: (*model).newEntry},
+		)
+		if m.entrySelected() {
+			commands = append(commands,
+				paletteCommand{title: "Open entry", binding: m.keys.enter, run: func(m *model) tea.Cmd {
+					cmd, _ := m.openSelectedEntry()
+					return cmd
+				}},
+				paletteCommand{title: "Open entry in $EDITOR", binding: m.keys.editor, run: (*model).openSelectedInEditor},
+				paletteCommand{title: "Export entry to Markdown", run: (*model).exportSelectedEntry},
+				paletteCommand{title: "Copy entry content", binding: m.keys.yank, run: (*model).yankSelected},
+				paletteCommand{title: "Copy entry ID", binding: m.keys.yankId, run: (*model).yankSelectedId},
+			)
+
+			// Bulk actions apply to marked entries or to the selected one
+			target := "entry"
+			if marked := len(m.entries.Marked()); marked > 0 {
+				target = entriesCount(marked)
+			}
+			commands = append(commands,
+				paletteCommand{title: "Delete " + target, binding: m.keys.delete, run: (*model).deleteMarked},
+				paletteCommand{title: "Tag " + target, binding: m.keys.tag, run: (*model).tagMarked},
+				paletteCommand{title: "Remove tags from " + target, binding: m.keys.untag, run: (*model).untagMarked},
+				paletteCommand{title: "Move " + target + " to journal", binding: m.keys.move, run: (*model).moveMarked},
+			)
+			if marked := len(m.entries.Marked()); marked > 0 {
+				commands = append(commands, paletteCommand{title: "Clear marks", run: func(m *model) tea.Cmd {
+					m.entries.ClearMarks()
+					return nil
+				}})
+			}
+		}
+		if m.hasDraft() {
+			commands = append(commands,
+				paletteCommand{title: "Restore unsaved draft", binding: m.keys.restore, run: (*model).restoreDraft},
+				paletteCommand{title: "Discard unsaved draft", run: (*model).dropDraft},
+			)
+		}
+		commands = append(commands,
+			paletteCommand{title: "Reverse entries sort order", binding: m.keys.sortOrder, run: func(m *model) tea.Cmd {
+				return m.changeEntriesSort(m.entriesSort.reversed())
+			}},
+			paletteCommand{title: "Sort entries by " + m.entriesSort.nextField().fieldLabel("title"), binding: m.keys.sortField, run: func(m *model) tea.Cmd {
+				return m.changeEntriesSort(m.entriesSort.nextField())
+			}},
+		)
+		if m.showCalendar {
+			commands = append(commands, paletteCommand{title: "Hide calendar", binding: m.keys.calendar, run: (*model).toggleCalendar})
+		} else {
+			commands = append(commands, paletteCommand{title: "Show calendar", binding: m.keys.calendar, run: (*model).toggleCalendar})
+		}
+		commands = append(commands,
+			paletteCommand{title: "Back to journals", binding: m.keys.esc, run: func(m *model) tea.Cmd {
+				m.closeJournal()
+				return nil
+			}},
+		)
+	case focusCalendar:
+		commands = append(commands,
+			paletteCommand{title: "New entry", run: (*model).newEntry},
+			paletteCommand{title: "Go to today", run: func(m *model) tea.Cmd {
+				return m.selectDay(time.Now())
+			}},
+			paletteCommand{title: "Show entries of the day", binding: m.keys.enter, run: func(m *model) tea.Cmd {
+				m.setFocusState(focusEntries)
+				return nil
+			}},
+			paletteCommand{title: "Hide calendar", binding: m.keys.calendar, run: (*model).toggleCalendar},
+		)
+	case focusEntry:
+		commands = append(commands,
+			paletteCommand{title: "New entry", run: (*model).newEntry},
+			paletteCommand{title: "Edit entry", binding: m.keys.edit, run: (*model).startEditing},
+		)
+		if m.viewer.Modified() {
+			commands = append(commands, paletteCommand{title: "Save entry", binding: m.keys.save, run: (*model).saveEdited})
+		}
+		if m.hasDraft() {
+			commands = append(commands,
+				paletteCommand{title: "Restore unsaved draft", binding: m.keys.restore, run: (*model).restoreDraft},
+				paletteCommand{title: "Discard unsaved draft", run: (*model).dropDraft},
+			)
+		}
+		commands = append(commands,
+			paletteCommand{title: "Open entry in $EDITOR", binding: m.keys.editor, run: (*model).openSelectedInEditor},
+			paletteCommand{title: "Export entry to Markdown", run: (*model).exportSelectedEntry},
+			paletteCommand{title: "Copy entry content", binding: m.keys.yank, run: (*model).yankSelected},
+			paletteCommand{title: "Copy entry ID", binding: m.keys.yankId, run: (*model).yankSelectedId},
+			paletteCommand{title: "Back to entries", binding: m.keys.esc, run: func(m *model) tea.Cmd {
+				m.setFocusState(focusEntries)
+				m.resizeComponents()
+				return nil
+			}},
+		)
+	}
+
+	// Loaded journals could be opened from any pane
+	for i, it := range m.journals.Items() {
+		ji, ok := it.(jItem)
+		if !ok {
+			continue
+		}
+		index := i
+		commands = append(commands, paletteCommand{
+			title: "Jump to journal: " + ji.journal.Name,
+			run: func(m *model) tea.Cmd {
+				m.journals.Select(index)
+				cmd, _ := m.openSelectedJournal()
+				return cmd
+			},
+		})
+	}
+
+	commands = append(commands,
+		paletteCommand{title: "Show error log", binding: m.keys.errorLog, run: func(m *model) tea.Cmd {
+			m.openErrorLog()
+			return nil
+		}},
+		paletteCommand{title: "Show help", binding: m.keys.help, run: func(m *model) tea.Cmd {
+			m.showHelp = true
+			return nil
+		}},
+	)
+	if m.retry != nil {
+		commands = append(commands, paletteCommand{title: "Retry failed load", binding: m.keys.retry, run: (*model).retryFailed})
+	}
+	commands = append(commands, paletteCommand{title: "Quit", binding: m.keys.forceQuit, run: func(m *model) tea.Cmd {
+		return tea.Quit
+	}})
+
+	return commands
+}
@@ -5,20 +5,47 @@ package tui
 import (
 	"fmt"
 
+	"github.com/kompotkot/firn/pkg/db"
 	"github.com/kompotkot/firn/pkg/kb"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// listFooterView renders footer line under the list with loading spinner
+func listFooterView(s spinner.Model, loading bool) string {
+	if !loading {
+		return ""
+	}
+	return listFooterStyle.Render(s.View() + " Loading more...")
+}
+
+// listHeight returns height available for the list, one line is kept for footer
+func listHeight(height int) int {
+	if height < 1 {
+		return 0
+	}
+	return height - 1
+}
+
 type journalsPane struct {
-	list list.Model
+	list    list.Model
+	spinner spinner.Model
+	pager   pageLoader
 }
 
 func newJournalsPane() journalsPane {
+	pager := newPageLoader(db.JOURNAL_LIST_DEFAULT_LIMIT)
+	pager.loading = true // First page is requested on start
+
 	return journalsPane{
-		list: initList("Journals"),
+		list:    initList("Journals"),
+		spinner: initSpinner(),
+		pager:   pager,
 	}
 }
 
@@ -28,20 +55,73 @@ func (p journalsPane) Update(msg tea.Msg) (journalsPane, tea.Cmd) {
 	return p, cmd
 }
 
+// UpdateSpinner advances loading spinner, it stops ticking once the page is loaded
+func (p journalsPane) UpdateSpinner(msg spinner.TickMsg) (journalsPane, tea.Cmd) {
+	if !p.pager.loading {
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.spinner, cmd = p.spinner.Update(msg)
+	return p, cmd
+}
+
 func (p journalsPane) View() string {
-	return p.list.View()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		p.list.View(),
+		listFooterView(p.spinner, p.pager.loading),
+	)
 }
 
 func (p *journalsPane) SetItems(items []list.Item) {
 	p.list.SetItems(items)
 }
 
-func (p *journalsPane) SetTotalPages(total int) {
-	p.list.Paginator.SetTotalPages(total)
+// AppendItems adds next loaded page of items to the end of the list
+func (p *journalsPane) AppendItems(items []list.Item) {
+	p.list.SetItems(append(p.list.Items(), items...))
 }
 
 func (p *journalsPane) SetSize(width, height int) {
-	p.list.SetSize(width, height)
+	p.list.SetSize(width, listHeight(height))
+}
+
+// SetFocused dims list title when another pane has focus
+func (p *journalsPane) SetFocused(focused bool) {
+	p.list.Styles.Title = listTitleStyle
+	if !focused {
+		p.list.Styles.Title = listBlurredTitleStyle
+	}
+}
+
+func (p *journalsPane) SetKeyMap(k keymap) {
+	p.list.KeyMap = k.listKeyMap()
+}
+
+// NeedsMore reports whether cursor approaches the end of loaded journals
+func (p journalsPane) NeedsMore() bool {
+	return p.pager.needsMore(p.list.GlobalIndex(), len(p.list.Items()), p.list.Paginator.PerPage)
+}
+
+// StartLoading marks next page as loading and returns its offset and limit
+// with a command to start the spinner
+func (p *journalsPane) StartLoading() (int, int, tea.Cmd) {
+	p.pager.loading = true
+	return p.pager.offset, p.pager.limit, p.spinner.Tick
+}
+
+// StopLoading aborts page loading, so it could be requested again
+func (p *journalsPane) StopLoading() {
+	p.pager.loading = false
+}
+
+// AcceptsPage reports whether a page loaded at offset is the one being waited for
+func (p journalsPane) AcceptsPage(offset int) bool {
+	return p.pager.accepts(offset)
+}
+
+// PageLoaded records page of count journals received at offset
+func (p *journalsPane) PageLoaded(offset, count int) {
+	p.pager.loaded(offset, count)
 }
 
 func (p journalsPane) SelectedJournal() (kb.Journal, bool) {
@@ -67,10 +147,41 @@ func (p *journalsPane) Select(index int) {
 	p.list.Select(index)
 }
 
+// ItemAt returns index of the journal rendered at row y of the pane
+func (p journalsPane) ItemAt(y int) (int, bool) {
+	return listItemAt(p.list, y)
+}
+
+func (p *journalsPane) CursorUp() {
+	p.list.CursorUp()
+}
+
+func (p *journalsPane) CursorDown() {
+	p.list.CursorDown()
+}
+
+func (p *journalsPane) Clear() {
+	p.list.SetItems([]list.Item{})
+	p.list.ResetSelected()
+	p.pager.reset()
+}
+
 func (p journalsPane) Items() []list.Item {
 	return p.list.Items()
 }
 
+// Refresh replaces loaded journals by reloaded ones keeping selection,
+// requested is a number of journals asked from the database
+func (p *journalsPane) Refresh(items []list.Item, requested int) {
+	replaceItems(&p.list, items)
+	p.pager.refreshed(len(items), requested)
+}
+
+// Loading reports whether a page request is in flight
+func (p journalsPane) Loading() bool {
+	return p.pager.loading
+}
+
 func (p *journalsPane) UpdateWidths(width int) {
 	currentItems := p.list.Items()
 	updated := make([]list.Item, len(currentItems))
@@ -87,12 +198,23 @@ func (p *journalsPane) UpdateWidths(width int) {
 }
 
 type entriesPane struct {
-	list list.Model
+	list    list.Model
+	spinner spinner.Model
+	pager   pageLoader
+	marked  map[string]bool // Entries marked for bulk actions, shared with list delegate
 }
 
 func newEntriesPane() entriesPane {
+	marked := make(map[string]bool)
+
+	l := initList("Entries")
+	l.SetDelegate(markDelegate{DefaultDelegate: newDelegate(), marked: marked})
+
 	return entriesPane{
-		list: initList("Entries"),
+		list:    l,
+		spinner: initSpinner(),
+		pager:   newPageLoader(db.ENTRY_LIST_DEFAULT_LIMIT),
+		marked:  marked,
 	}
 }
 
@@ -102,20 +224,77 @@ func (p entriesPane) Update(msg tea.Msg) (entriesPane, tea.Cmd) {
 	return p, cmd
 }
 
+// UpdateSpinner advances loading spinner, it stops ticking once the page is loaded
+func (p entriesPane) UpdateSpinner(msg spinner.TickMsg) (entriesPane, tea.Cmd) {
+	if !p.pager.loading {
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.spinner, cmd = p.spinner.Update(msg)
+	return p, cmd
+}
+
 func (p entriesPane) View() string {
-	return p.list.View()
+	return lipgloss.JoinVertical(lipgloss.Left,
+		p.list.View(),
+		listFooterView(p.spinner, p.pager.loading),
+	)
 }
 
 func (p *entriesPane) SetItems(items []list.Item) {
 	p.list.SetItems(items)
 }
 
-func (p *entriesPane) SetTotalPages(total int) {
-	p.list.Paginator.SetTotalPages(total)
+// AppendItems adds next loaded page of items to the end of the list
+func (p *entriesPane) AppendItems(items []list.Item) {
+	p.list.SetItems(append(p.list.Items(), items...))
 }
 
 func (p *entriesPane) SetSize(width, height int) {
-	p.list.SetSize(width, height)
+	p.list.SetSize(width, listHeight(height))
+}
+
+// SetFocused dims list title when another pane has focus
+func (p *entriesPane) SetFocused(focused bool) {
+	p.list.Styles.Title = listTitleStyle
+	if !focused {
+		p.list.Styles.Title = listBlurredTitleStyle
+	}
+}
+
+func (p *entriesPane) SetKeyMap(k keymap) {
+	p.list.KeyMap = k.listKeyMap()
+}
+
+// NeedsMore reports whether cursor approaches the end of loaded entries
+func (p entriesPane) NeedsMore() bool {
+	return p.pager.needsMore(p.list.GlobalIndex(), len(p.list.Items()), p.list.Paginator.PerPage)
+}
+
+// StartLoading marks next page as loading and returns its offset and limit
+// with a command to start the spinner
+func (p *entriesPane) StartLoading() (int, int, tea.Cmd) {
+	p.pager.loading = true
+	return p.pager.offset, p.pager.limit, p.spinner.Tick
+}
+
+// StopLoading aborts page loading, so it could be requested again
+func (p *entriesPane) StopLoading() {
+	p.pager.loading = false
+}
+
+// AcceptsPage reports whether a page loaded at offset is the one being waited for
+func (p entriesPane) AcceptsPage(offset int) bool {
+	return p.pager.accepts(offset)
+}
+
+// PageLoaded records page of count entries received at offset
+func (p *entriesPane) PageLoaded(offset, count int) {
+	p.pager.loaded(offset, count)
+}
+
+func (p *journalsPane) SetTitle(title string) {
+	p.list.Title = title
 }
 
 func (p *entriesPane) SetTitle(title string) {
@@ -137,10 +316,43 @@ func (p entriesPane) SelectedEntry() (kb.Entry, bool) {
 	return kb.Entry{}, false
 }
 
+// ItemAt returns index of the entry rendered at row y of the pane
+func (p entriesPane) ItemAt(y int) (int, bool) {
+	return listItemAt(p.list, y)
+}
+
+func (p entriesPane) GlobalIndex() int {
+	return p.list.GlobalIndex()
+}
+
+func (p *entriesPane) Select(index int) {
+	p.list.Select(index)
+}
+
+func (p *entriesPane) CursorUp() {
+	p.list.CursorUp()
+}
+
+func (p *entriesPane) CursorDown() {
+	p.list.CursorDown()
+}
+
 func (p entriesPane) Items() []list.Item {
 	return p.list.Items()
 }
 
+// Refresh replaces loaded entries by reloaded ones keeping selection,
+// requested is a number of entries asked from the database
+func (p *entriesPane) Refresh(items []list.Item, requested int) {
+	replaceItems(&p.list, items)
+	p.pager.refreshed(len(items), requested)
+}
+
+// Loading reports whether a page request is in flight
+func (p entriesPane) Loading() bool {
+	return p.pager.loading
+}
+
 func (p *entriesPane) UpdateWidths(width int) {
 	currentItems := p.list.Items()
 	updated := make([]list.Item, len(currentItems))
@@ -157,50 +369,290 @@ func (p *entriesPane) UpdateWidths(width int) {
 	p.list.SetItems(updated)
 }
 
+// Entry returns loaded entry with the given ID
+func (p entriesPane) Entry(id string) (kb.Entry, bool) {
+	for _, it := range p.list.Items() {
+		if ei, ok := it.(eItem); ok && ei.entry.Id == id {
+			return ei.entry, true
+		}
+	}
+	return kb.Entry{}, false
+}
+
+// UpdateEntry replaces entry item with the same ID by saved entry
+func (p *entriesPane) UpdateEntry(entry kb.Entry) {
+	for i, it := range p.list.Items() {
+		if ei, ok := it.(eItem); ok && ei.entry.Id == entry.Id {
+			ei.entry = entry
+			p.list.SetItem(i, ei)
+			return
+		}
+	}
+}
+
+// AddEntry inserts created entry item at the top or at the end of the list
+// and selects it, the entry is left for later pages if they are not loaded yet
+func (p *entriesPane) AddEntry(item list.Item, first bool) bool {
+	if !first && p.pager.hasMore {
+		return false
+	}
+
+	index := 0
+	if !first {
+		index = len(p.list.Items())
+	}
+	p.list.InsertItem(index, item)
+	p.list.Select(index)
+	p.pager.shift(1)
+
+	return true
+}
+
 func (p *entriesPane) Clear() {
 	p.list.SetItems([]list.Item{})
-	p.list.Paginator.SetTotalPages(0)
+	p.list.ResetSelected()
+	p.pager.reset()
+	p.ClearMarks()
+}
+
+// ToggleMark marks selected entry for bulk actions or unmarks it
+func (p *entriesPane) ToggleMark() {
+	entry, ok := p.SelectedEntry()
+	if !ok {
+		return
+	}
+	if p.marked[entry.Id] {
+		delete(p.marked, entry.Id)
+		return
+	}
+	p.marked[entry.Id] = true
+}
+
+// Marked returns IDs of marked entries in list order, marks of entries
+// which are not in the list anymore are skipped
+func (p entriesPane) Marked() []string {
+	var ids []string
+	for _, it := range p.list.Items() {
+		if ei, ok := it.(eItem); ok && p.marked[ei.entry.Id] {
+			ids = append(ids, ei.entry.Id)
+		}
+	}
+	return ids
 }
 
-// Entry viewer textarea
+// ClearMarks unmarks all entries, map is cleared in place as it is shared
+// with list delegate
+func (p *entriesPane) ClearMarks() {
+	clear(p.marked)
+}
+
+// RemoveEntries drops entries deleted or moved to another journal
+func (p *entriesPane) RemoveEntries(ids []string) {
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+		delete(p.marked, id)
+	}
+
+	items := make([]list.Item, 0, len(p.list.Items()))
+	for _, it := range p.list.Items() {
+		if ei, ok := it.(eItem); ok && removed[ei.entry.Id] {
+			continue
+		}
+		items = append(items, it)
+	}
+
+	p.pager.shift(len(items) - len(p.list.Items()))
+	replaceItems(&p.list, items)
+}
+
+// Entry viewer, renders content as Markdown in read mode and switches
+// to textarea for editing
 
 type entryViewer struct {
 	textarea textarea.Model
+	viewport viewport.Model
+	markdown markdownRenderer
+
+	content  string // Raw entry content
+	original string // Content as saved in the database
+	focused  bool
+	editing  bool
 }
 
 func newEntryViewer() entryViewer {
 	return entryViewer{
 		textarea: initTextarea(),
+		viewport: initViewport(),
+		markdown: newMarkdownRenderer(),
 	}
 }
 
 func (v entryViewer) Update(msg tea.Msg) (entryViewer, tea.Cmd) {
 	var cmd tea.Cmd
-	v.textarea, cmd = v.textarea.Update(msg)
+	switch {
+	case v.editing:
+		v.textarea, cmd = v.textarea.Update(msg)
+	case v.focused:
+		// Scroll rendered content only when viewer has focus
+		v.viewport, cmd = v.viewport.Update(msg)
+	}
 	return v, cmd
 }
 
 func (v entryViewer) View() string {
-	return v.textarea.View()
+	if v.editing {
+		return v.textarea.View()
+	}
+	return v.viewport.View()
 }
 
 func (v *entryViewer) SetSize(width, height int) {
 	v.textarea.SetWidth(width)
 	v.textarea.SetHeight(height)
+
+	resized := v.viewport.Width != width
+	v.viewport.Width = width
+	v.viewport.Height = height
+	if resized {
+		v.render()
+	}
+}
+
+func (v *entryViewer) SetKeyMap(k keymap) {
+	v.viewport.KeyMap = k.viewportKeyMap()
 }
 
 func (v *entryViewer) SetContent(value string) {
+	v.content = value
+	v.original = value
 	v.textarea.SetValue(value)
+	v.render()
+	v.viewport.GotoTop()
+}
+
+// SetDraft puts unsaved content into textarea, saved content is kept to
+// detect changes
+func (v *entryViewer) SetDraft(value string) {
+	v.content = value
+	v.textarea.SetValue(value)
+	v.render()
+}
+
+// SetSaved records content saved in the database without touching textarea,
+// so text typed while saving is kept
+func (v *entryViewer) SetSaved(value string) {
+	v.original = value
+}
+
+// Value returns content of textarea
+func (v entryViewer) Value() string {
+	return v.textarea.Value()
+}
+
+// Modified reports whether textarea content differs from saved content
+func (v entryViewer) Modified() bool {
+	return v.textarea.Value() != v.original
+}
+
+// render updates read mode viewport with Markdown rendered content
+func (v *entryViewer) render() {
+	if v.content == "" {
+		v.viewport.SetContent(viewerPlaceholderStyle.Render(v.textarea.Placeholder))
+		return
+	}
+	v.viewport.SetContent(v.markdown.Render(v.content, v.viewport.Width))
 }
 
 func (v entryViewer) Focused() bool {
-	return v.textarea.Focused()
+	return v.focused
 }
 
 func (v *entryViewer) Focus() {
-	v.textarea.Focus()
+	v.focused = true
+	if v.editing {
+		v.textarea.Focus()
+	}
 }
 
 func (v *entryViewer) Blur() {
+	v.StopEditing()
+	v.focused = false
+}
+
+// Scroll moves read mode content by the given number of lines, negative
+// values scroll up
+func (v *entryViewer) Scroll(lines int) {
+	if v.editing {
+		return
+	}
+	if lines < 0 {
+		v.viewport.ScrollUp(-lines)
+		return
+	}
+	v.viewport.ScrollDown(lines)
+}
+
+func (v entryViewer) Editing() bool {
+	return v.editing
+}
+
+// StartEditing switches viewer from read mode to textarea
+func (v *entryViewer) StartEditing() {
+	v.editing = true
+	v.textarea.Focus()
+}
+
+// StopEditing switches viewer back to read mode, rendering edited content
+func (v *entryViewer) StopEditing() {
+	if !v.editing {
+		return
+	}
+	v.editing = false
 	v.textarea.Blur()
+
+	v.content = v.textarea.Value()
+	v.render()
+}
+
+// Error log viewer
+
+type errorLogPane struct {
+	viewport viewport.Model
+}
+
+func newErrorLogPane() errorLogPane {
+	vp := initViewport()
+	vp.Style = errorLogStyle
+
+	return errorLogPane{
+		viewport: vp,
+	}
+}
+
+func (p errorLogPane) Update(msg tea.Msg) (errorLogPane, tea.Cmd) {
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return p, cmd
+}
+
+func (p errorLogPane) View() string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		errorLogTitleStyle.Render("Error log"),
+		p.viewport.View(),
+	)
+}
+
+func (p *errorLogPane) SetSize(width, height int) {
+	p.viewport.Width = width
+	p.viewport.Height = max(height-2, 0) // Title with bottom padding
+}
+
+func (p *errorLogPane) SetKeyMap(k keymap) {
+	p.viewport.KeyMap = k.viewportKeyMap()
+}
+
+func (p *errorLogPane) SetContent(content string) {
+	p.viewport.SetContent(content)
+	p.viewport.GotoTop()
 }
@@ -0,0 +1,109 @@
+//go:build tui
+
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptLine asks for a value or for confirmation in place of status line
+type promptLine struct {
+	input   textinput.Model
+	confirm bool // Question is answered with y or n instead of typed value
+	submit  func(m *model, value string) tea.Cmd
+}
+
+func newPromptLine() promptLine {
+	ti := textinput.New()
+	ti.PromptStyle = viewerPromptStyle
+	ti.TextStyle = viewerTextStyle
+	ti.PlaceholderStyle = viewerPlaceholderStyle
+
+	return promptLine{input: ti}
+}
+
+// Open asks question, submit is called with typed value or with empty
+// value when confirmation is accepted
+func (p *promptLine) Open(question string, confirm bool, submit func(m *model, value string) tea.Cmd) {
+	p.confirm = confirm
+	p.submit = submit
+	p.input.SetValue("")
+	p.input.Placeholder = ""
+	p.input.Prompt = question + " "
+	if confirm {
+		p.input.Prompt = question + " [y/N] "
+	}
+	p.input.Focus()
+}
+
+func (p *promptLine) Close() {
+	p.input.Blur()
+	p.submit = nil
+}
+
+func (p promptLine) Update(msg tea.KeyMsg) (promptLine, tea.Cmd) {
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return p, cmd
+}
+
+// Value returns typed value without surrounding spaces
+func (p promptLine) Value() string {
+	return strings.TrimSpace(p.input.Value())
+}
+
+func (p promptLine) View(width int) string {
+	return statusStyle.Width(width).MaxHeight(1).Render(p.input.View())
+}
+
+// ask shows prompt for a value in place of status line
+func (m *model) ask(question string, submit func(m *model, value string) tea.Cmd) {
+	m.showPrompt = true
+	m.prompt.Open(question, false, submit)
+}
+
+// confirm shows yes or no question in place of status line, submit is
+// called only when it is accepted
+func (m *model) confirm(question string, submit func(m *model) tea.Cmd) {
+	m.showPrompt = true
+	m.prompt.Open(question, true, func(m *model, _ string) tea.Cmd {
+		return submit(m)
+	})
+}
+
+func (m *model) closePrompt() {
+	m.showPrompt = false
+	m.prompt.Close()
+}
+
+// handlePromptKey answers shown prompt, confirmation is accepted by y and
+// declined by any other key
+func (m *model) handlePromptKey(msg tea.KeyMsg) tea.Cmd {
+	submit := m.prompt.submit
+
+	switch {
+	case msg.Type == tea.KeyEsc:
+		m.closePrompt()
+		return nil
+	case m.prompt.confirm:
+		m.closePrompt()
+		if msg.String() == "y" || msg.String() == "Y" {
+			return submit(m, "")
+		}
+		return m.status.Push(severityInfo, "Cancelled")
+	case msg.Type == tea.KeyEnter:
+		value := m.prompt.Value()
+		m.closePrompt()
+		if value == "" {
+			return nil
+		}
+		return submit(m, value)
+	}
+
+	var cmd tea.Cmd
+	m.prompt, cmd = m.prompt.Update(msg)
+	return cmd
+}
@@ -0,0 +1,206 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type refreshTickMsg struct{}
+
+// journalsRefreshedMsg carries loaded journals reloaded from the start,
+// they are not reloaded if change marker is the same as before
+type journalsRefreshedMsg struct {
+	marker    db.ChangeMarker
+	unchanged bool
+	sort      listSort
+	requested int
+	journals  []kb.Journal
+}
+
+// entriesRefreshedMsg carries loaded entries reloaded from the start,
+// they are not reloaded if change marker is the same as before
+type entriesRefreshedMsg struct {
+	marker    db.ChangeMarker
+	unchanged bool
+	journalId string
+	day       time.Time
+	sort      listSort
+	requested int
+	entries   []kb.Entry
+}
+
+// entriesMarker is a change marker of entries listed for a journal and day
+type entriesMarker struct {
+	journalId string
+	day       time.Time
+	marker    db.ChangeMarker
+}
+
+// refreshFailedMsg is reported once until refresh succeeds again, so
+// unavailable database does not flood error log every tick
+type refreshFailedMsg struct {
+	operation string
+	err       error
+}
+
+// refreshTick schedules next poll of the database
+func refreshTick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+// Reload first count journals to detect changes made by other clients,
+// journals are reloaded only if their change marker differs from known one
+func refreshJournals(ctx context.Context, database db.Database, known db.ChangeMarker, sort listSort, count int) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		marker, err := database.GetJournalsMarker(currentCtx)
+		if err != nil {
+			return refreshFailedMsg{operation: "refreshJournals", err: err}
+		}
+		if marker == known {
+			return journalsRefreshedMsg{marker: marker, unchanged: true, sort: sort}
+		}
+
+		journals, err := database.ListJournals(currentCtx, sort.OrderBy, sort.Desc, count, 0)
+		if err != nil {
+			return refreshFailedMsg{operation: "refreshJournals", err: err}
+		}
+		return journalsRefreshedMsg{marker: marker, sort: sort, requested: count, journals: journals}
+	}
+}
+
+// Reload first count entries of journal, optionally created on a day,
+// entries are reloaded only if their change marker differs from known one
+func refreshEntries(ctx context.Context, database db.Database, known db.ChangeMarker, journalId string, day time.Time, sort listSort, count int) tea.Cmd {
+	return func() tea.Msg {
+		currentCtx := ctx
+		if currentCtx == nil {
+			currentCtx = context.Background()
+		}
+
+		operation := fmt.Sprintf("refreshEntries(%s)", journalId)
+
+		var to time.Time
+		if !day.IsZero() {
+			to = day.AddDate(0, 0, 1)
+		}
+		marker, err := database.GetEntriesMarker(currentCtx, journalId, day, to)
+		if err != nil {
+			return refreshFailedMsg{operation: operation, err: err}
+		}
+		if marker == known {
+			return entriesRefreshedMsg{marker: marker, unchanged: true, journalId: journalId, day: day, sort: sort}
+		}
+
+		var entries []kb.Entry
+		if day.IsZero() {
+			entries, err = database.ListEntries(currentCtx, journalId, sort.OrderBy, sort.Desc, count, 0)
+		} else {
+			entries, err = database.ListEntriesCreatedBetween(currentCtx, journalId, day, to, sort.OrderBy, sort.Desc, count, 0)
+		}
+		if err != nil {
+			return refreshFailedMsg{operation: operation, err: err}
+		}
+		return entriesRefreshedMsg{marker: marker, journalId: journalId, day: day, sort: sort, requested: count, entries: entries}
+	}
+}
+
+// refresh polls the database for loaded journals and entries, lists which
+// are loading next page are skipped until the next tick. Lists are reloaded
+// only when change markers of their rows differ from the last refresh.
+func (m *model) refresh() tea.Cmd {
+	var cmds []tea.Cmd
+
+	if !m.journals.Loading() {
+		count := max(len(m.journals.Items()), db.JOURNAL_LIST_DEFAULT_LIMIT)
+		cmds = append(cmds, refreshJournals(m.ctx, m.database, m.journalsMarker, m.journalsSort, count))
+	}
+
+	if m.entriesViewActive() && !m.entries.Loading() {
+		var known db.ChangeMarker
+		day := m.entriesDay()
+		if m.entriesMarker.journalId == m.selectedJournalId && m.entriesMarker.day.Equal(day) {
+			known = m.entriesMarker.marker
+		}
+		count := max(len(m.entries.Items()), db.ENTRY_LIST_DEFAULT_LIMIT)
+		cmds = append(cmds, refreshEntries(m.ctx, m.database, known, m.selectedJournalId, day, m.entriesSort, count))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// applyJournalsRefresh shows changed journals keeping selection
+func (m *model) applyJournalsRefresh(msg journalsRefreshedMsg) {
+	// Drop results of unchanged rows, of previous sort or of list being reloaded
+	if msg.unchanged || msg.sort != m.journalsSort || m.journals.Loading() {
+		return
+	}
+	m.journalsMarker = msg.marker
+
+	journalsWidth, _, _ := m.columnWidths()
+	items := make([]list.Item, len(msg.journals))
+	for i, j := range msg.journals {
+		items[i] = newJItem(j, journalsWidth)
+	}
+	if sameItems(m.journals.Items(), items) {
+		return
+	}
+
+	m.journals.Refresh(items, msg.requested)
+	m.journals.UpdateWidths(journalsWidth)
+}
+
+// applyEntriesRefresh shows changed entries keeping selection, content of
+// selected entry is reloaded unless it has unsaved edits
+func (m *model) applyEntriesRefresh(msg entriesRefreshedMsg) tea.Cmd {
+	if msg.unchanged || msg.journalId != m.selectedJournalId || !msg.day.Equal(m.entriesDay()) || msg.sort != m.entriesSort || m.entries.Loading() {
+		return nil
+	}
+	m.entriesMarker = entriesMarker{journalId: msg.journalId, day: msg.day, marker: msg.marker}
+
+	_, entriesWidth, _ := m.columnWidths()
+	items := make([]list.Item, len(msg.entries))
+	for i, e := range msg.entries {
+		items[i] = newEItem(e, entriesWidth)
+	}
+	if sameItems(m.entries.Items(), items) {
+		return nil
+	}
+
+	previous, hadPrevious := m.entries.Entry(m.selectedEntryId)
+	m.entries.Refresh(items, msg.requested)
+	m.entries.UpdateWidths(entriesWidth)
+
+	// Edited entry stays in viewer even if it was changed or deleted
+	if m.viewer.Editing() || m.viewer.Modified() {
+		current, ok := m.entries.Entry(m.selectedEntryId)
+		switch {
+		case !ok:
+			return m.status.Push(severityWarn, "Edited entry was deleted from database")
+		case hadPrevious && !current.UpdatedAt.Equal(previous.UpdatedAt):
+			return m.status.Push(severityWarn, fmt.Sprintf("Entry %q was changed in database while editing", current.Title))
+		}
+		return nil
+	}
+
+	current, ok := m.entries.SelectedEntry()
+	if ok && current.Id == m.selectedEntryId && hadPrevious && !current.UpdatedAt.Equal(previous.UpdatedAt) {
+		return getEntryById(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId)
+	}
+	return m.syncSelectedEntry(m.selectedEntryId)
+}
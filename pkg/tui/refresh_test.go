@@ -0,0 +1,152 @@
+//go:build tui
+
+package tui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kompotkot/firn/pkg/db"
+	"github.com/kompotkot/firn/pkg/kb"
+)
+
+// refreshDB returns journals and entries with their change markers and
+// counts how many times lists were loaded
+type refreshDB struct {
+	db.Database
+	journals       []kb.Journal
+	entries        []kb.Entry
+	journalsMarker db.ChangeMarker
+	entriesMarker  db.ChangeMarker
+	journalLists   int
+	entryLists     int
+}
+
+func (d *refreshDB) GetJournalsMarker(ctx context.Context) (db.ChangeMarker, error) {
+	return d.journalsMarker, nil
+}
+
+func (d *refreshDB) GetEntriesMarker(ctx context.Context, journalId string, from, to time.Time) (db.ChangeMarker, error) {
+	return d.entriesMarker, nil
+}
+
+func (d *refreshDB) ListJournals(ctx context.Context, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Journal, error) {
+	d.journalLists++
+	return d.journals, nil
+}
+
+func (d *refreshDB) ListEntries(ctx context.Context, journalId string, orderBy db.OrderBy, orderByDesc bool, limit, offset int) ([]kb.Entry, error) {
+	d.entryLists++
+	return d.entries, nil
+}
+
+func (d *refreshDB) GetEntryById(ctx context.Context, journalId, entryId string) (*kb.Entry, error) {
+	for _, e := range d.entries {
+		if e.Id == entryId {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestRefresh(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	database := &refreshDB{
+		journals:       []kb.Journal{{Id: "j1", Name: "Personal", UpdatedAt: updated}},
+		entries:        []kb.Entry{{Id: "e1", JournalId: "j1"}, {Id: "e2", JournalId: "j1"}, {Id: "e3", JournalId: "j1"}, {Id: "e4", JournalId: "j1"}},
+		journalsMarker: db.ChangeMarker{Count: 1, UpdatedAt: "2024-01-02 10:00:00"},
+		entriesMarker:  db.ChangeMarker{Count: 4, UpdatedAt: "2024-01-02 10:00:00"},
+	}
+	m := entriesModel(t, nil, 0)
+	m.database = database
+	loaded, _ := m.Update(journalsLoadedMsg{journals: database.journals})
+	m = loaded.(model)
+
+	// Steps run one after another on the same model
+	tests := []struct {
+		name             string
+		change           func()
+		wantJournalLists int // Total number of list loads
+		wantEntryLists   int
+		wantEntries      int
+	}{
+		{
+			name:             "first refresh",
+			change:           func() {},
+			wantJournalLists: 1,
+			wantEntryLists:   1,
+			wantEntries:      4,
+		},
+		{
+			name:             "unchanged",
+			change:           func() {},
+			wantJournalLists: 1,
+			wantEntryLists:   1,
+			wantEntries:      4,
+		},
+		{
+			name: "entry deleted",
+			change: func() {
+				database.entries = database.entries[:3]
+				database.entriesMarker.Count = 3
+			},
+			wantJournalLists: 1,
+			wantEntryLists:   2,
+			wantEntries:      3,
+		},
+		{
+			name: "entry updated",
+			change: func() {
+				database.entries[1].UpdatedAt = updated.Add(time.Minute)
+				database.entriesMarker.UpdatedAt = "2024-01-02 10:00:00.500"
+			},
+			wantJournalLists: 1,
+			wantEntryLists:   3,
+			wantEntries:      3,
+		},
+		{
+			name: "journal renamed",
+			change: func() {
+				database.journals[0].Name = "Diary"
+				database.journals[0].UpdatedAt = updated.Add(time.Hour)
+				database.journalsMarker.UpdatedAt = "2024-01-02 11:00:00"
+			},
+			wantJournalLists: 2,
+			wantEntryLists:   3,
+			wantEntries:      3,
+		},
+		{
+			name: "other journal selected",
+			change: func() {
+				m.selectedJournalId = "j2"
+				for i := range database.entries {
+					database.entries[i].JournalId = "j2"
+				}
+			},
+			wantJournalLists: 2,
+			wantEntryLists:   4,
+			wantEntries:      3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			for _, msg := range collectMsgs(m.refresh()()) {
+				updated, _ := m.Update(msg)
+				m = updated.(model)
+			}
+
+			if database.journalLists != tt.wantJournalLists || database.entryLists != tt.wantEntryLists {
+				t.Errorf("lists loaded = %d journals and %d entries, want %d and %d", database.journalLists, database.entryLists, tt.wantJournalLists, tt.wantEntryLists)
+			}
+			if got := len(m.entries.Items()); got != tt.wantEntries {
+				t.Errorf("entries = %d, want %d", got, tt.wantEntries)
+			}
+			if got := m.journals.Items()[0].(jItem).journal.Name; got != database.journals[0].Name {
+				t.Errorf("journal name = %q, want %q", got, database.journals[0].Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+//go:build tui
+
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kompotkot/firn/pkg/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listSort is a field and direction journals or entries list is sorted by
+type listSort struct {
+	OrderBy db.OrderBy `toml:"order_by"`
+	Desc    bool       `toml:"desc"`
+}
+
+func defaultSort() listSort {
+	return listSort{OrderBy: db.OrderByUpdated}
+}
+
+// valid returns sort with unknown field replaced by default one
+func (s listSort) valid() listSort {
+	if !s.OrderBy.Valid() {
+		s.OrderBy = db.OrderByUpdated
+	}
+	return s
+}
+
+// nextField returns sort by the next field with the same direction
+func (s listSort) nextField() listSort {
+	i := slices.Index(db.OrderByFields, s.OrderBy)
+	s.OrderBy = db.OrderByFields[(i+1)%len(db.OrderByFields)]
+	return s
+}
+
+// reversed returns sort by the same field in opposite direction
+func (s listSort) reversed() listSort {
+	s.Desc = !s.Desc
+	return s
+}
+
+// fieldLabel returns field name, name field is labeled by nameLabel
+func (s listSort) fieldLabel(nameLabel string) string {
+	if s.OrderBy == db.OrderByName {
+		return nameLabel
+	}
+	return string(s.OrderBy)
+}
+
+// label returns short sort description shown in list title
+func (s listSort) label(nameLabel string) string {
+	arrow := "↑"
+	if s.Desc {
+		arrow = "↓"
+	}
+	return fmt.Sprintf("%s %s", s.fieldLabel(nameLabel), arrow)
+}
+
+// describe returns sort description shown in status line
+func (s listSort) describe(nameLabel string) string {
+	order := "oldest first"
+	switch {
+	case s.OrderBy == db.OrderByName && s.Desc:
+		order = "Z to A"
+	case s.OrderBy == db.OrderByName:
+		order = "A to Z"
+	case s.Desc:
+		order = "newest first"
+	}
+	return fmt.Sprintf("by %s, %s", s.fieldLabel(nameLabel), order)
+}
+
+// journalsTitle returns title of journals list with its sort
+func (m model) journalsTitle() string {
+	return fmt.Sprintf("Journals (%s)", m.journalsSort.label("name"))
+}
+
+// changeJournalsSort loads journals sorted by s from the start
+func (m *model) changeJournalsSort(s listSort) tea.Cmd {
+	m.journalsSort = s
+	m.journals.Clear()
+	m.journals.SetTitle(m.journalsTitle())
+	m.lastJournalIndex = -1
+
+	return tea.Batch(
+		m.loadMoreJournals(),
+		m.status.Push(severityInfo, "Journals sorted "+s.describe("name")),
+	)
+}
+
+// changeEntriesSort loads entries sorted by s from the start
+func (m *model) changeEntriesSort(s listSort) tea.Cmd {
+	if !m.entriesViewActive() {
+		return nil
+	}
+
+	m.entriesSort = s
+	if m.focusState == focusEntry {
+		m.setFocusState(focusEntries)
+	}
+
+	return tea.Batch(
+		m.reloadEntries(),
+		m.status.Push(severityInfo, "Entries sorted "+s.describe("title")),
+	)
+}
+
+// sortFocusedList changes sort of the list in focus, reverse flips direction
+// and otherwise list is sorted by the next field
+func (m *model) sortFocusedList(reverse bool) tea.Cmd {
+	change := listSort.nextField
+	if reverse {
+		change = listSort.reversed
+	}
+
+	switch {
+	case m.focusState == focusJournals:
+		return m.changeJournalsSort(change(m.journalsSort))
+	case m.entriesViewActive():
+		return m.changeEntriesSort(change(m.entriesSort))
+	}
+	return nil
+}
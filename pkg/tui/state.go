@@ -0,0 +1,100 @@
+//go:build tui
+
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Default, minimal and maximal share of entries list in split with viewer, in percents
+const (
+	defaultSplitRatio = 60
+	minSplitRatio     = 20
+	maxSplitRatio     = 80
+	splitRatioStep    = 5
+)
+
+// tuiState is a state of the TUI remembered between sessions
+type tuiState struct {
+	SplitRatio   int      `toml:"split_ratio"`
+	JournalsSort listSort `toml:"journals_sort"`
+	EntriesSort  listSort `toml:"entries_sort"`
+}
+
+func defaultState() tuiState {
+	return tuiState{
+		SplitRatio:   defaultSplitRatio,
+		JournalsSort: defaultSort(),
+		EntriesSort:  defaultSort(),
+	}
+}
+
+// stateFilePath returns path to state file in XDG_STATE_HOME,
+// by default ~/.local/state/firn/tui.toml
+func stateFilePath() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+
+	return filepath.Join(stateDir, "firn", "tui.toml")
+}
+
+// loadState reads state of previous session, defaults are used for
+// missing or broken state file as it is not edited by user
+func loadState() tuiState {
+	state := defaultState()
+
+	path := stateFilePath()
+	if path == "" {
+		return state
+	}
+
+	if _, err := toml.DecodeFile(path, &state); err != nil {
+		return defaultState()
+	}
+	state.SplitRatio = clampSplitRatio(state.SplitRatio)
+	state.JournalsSort = state.JournalsSort.valid()
+	state.EntriesSort = state.EntriesSort.valid()
+
+	return state
+}
+
+// saveState writes state to be restored in the next session
+func saveState(state tuiState) error {
+	path := stateFilePath()
+	if path == "" {
+		return errors.New("state directory is not available")
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(state); err != nil {
+		return fmt.Errorf("failed to encode TUI state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+func clampSplitRatio(ratio int) int {
+	switch {
+	case ratio < minSplitRatio:
+		return minSplitRatio
+	case ratio > maxSplitRatio:
+		return maxSplitRatio
+	}
+	return ratio
+}
@@ -0,0 +1,140 @@
+//go:build tui
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type statusSeverity int
+
+const (
+	severityInfo statusSeverity = iota
+	severityWarn
+	severityError
+)
+
+func (s statusSeverity) String() string {
+	switch s {
+	case severityWarn:
+		return "WARN"
+	case severityError:
+		return "ERROR"
+	default:
+		return "INFO"
+	}
+}
+
+// How long info messages stay in status line
+const statusInfoTimeout = 3 * time.Second
+
+// Maximum number of messages kept in error log
+const errorLogLimit = 100
+
+type statusMessage struct {
+	id       int
+	severity statusSeverity
+	text     string
+	at       time.Time
+}
+
+type statusExpiredMsg struct {
+	id int
+}
+
+// statusBar shows the latest message in footer and keeps log of warnings and errors
+type statusBar struct {
+	current *statusMessage
+	nextId  int
+	log     []statusMessage
+}
+
+// Push shows message in status line, info messages are dismissed after timeout
+func (s *statusBar) Push(severity statusSeverity, text string) tea.Cmd {
+	s.nextId++
+	msg := statusMessage{id: s.nextId, severity: severity, text: text, at: time.Now()}
+	s.current = &msg
+
+	if severity == severityInfo {
+		id := msg.id
+		return tea.Tick(statusInfoTimeout, func(time.Time) tea.Msg {
+			return statusExpiredMsg{id: id}
+		})
+	}
+
+	s.log = append(s.log, msg)
+	if len(s.log) > errorLogLimit {
+		s.log = s.log[len(s.log)-errorLogLimit:]
+	}
+
+	return nil
+}
+
+// Expire dismisses message if it is still shown
+func (s *statusBar) Expire(id int) {
+	if s.current != nil && s.current.id == id {
+		s.current = nil
+	}
+}
+
+// Dismiss clears status line
+func (s *statusBar) Dismiss() {
+	s.current = nil
+}
+
+// Severity returns severity of shown message
+func (s statusBar) Severity() (statusSeverity, bool) {
+	if s.current == nil {
+		return severityInfo, false
+	}
+	return s.current.severity, true
+}
+
+func (s statusBar) View(width int) string {
+	if s.current == nil {
+		return statusStyle.Width(width).Render("")
+	}
+
+	var style lipgloss.Style
+	switch s.current.severity {
+	case severityWarn:
+		style = statusWarnStyle
+	case severityError:
+		style = statusErrorStyle
+	default:
+		style = statusInfoStyle
+	}
+
+	label := style.Render(s.current.severity.String())
+	text := strings.ReplaceAll(s.current.text, "\n", " ")
+
+	return statusStyle.Width(width).MaxHeight(1).Render(fmt.Sprintf("%s %s", label, text))
+}
+
+// LogView renders error log with the newest messages first
+func (s statusBar) LogView() string {
+	if len(s.log) == 0 {
+		return viewerPlaceholderStyle.Render("No errors")
+	}
+
+	lines := make([]string, 0, len(s.log))
+	for i := len(s.log) - 1; i >= 0; i-- {
+		msg := s.log[i]
+
+		var style lipgloss.Style
+		if msg.severity == severityError {
+			style = statusErrorStyle
+		} else {
+			style = statusWarnStyle
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s %s", msg.at.Format(datetimeFormat), style.Render(msg.severity.String()), msg.text))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -4,9 +4,7 @@ package tui
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -17,63 +15,179 @@ var (
 	// 3 - from different paddings to not cut datetime at right side
 	magicWidthPaddingNum = 3
 
-	// appStyle = lipgloss.NewStyle().Padding(1, 2)
+	rightPaddingDatetime = 3 // from different paddings to not cut datetime at right side
+)
 
+// Styles of the TUI, they are set from the active theme by applyTheme
+var (
 	// --- Header ----
-	// Get terminal default colors and invert them
-	// Use AdaptiveColor to get colors based on terminal theme
-	// headerStyle = invertFBGColors(
-	// 	lipgloss.NewStyle().
-	// 		Foreground(lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"}). // Default text color
-	// 		Background(lipgloss.AdaptiveColor{Light: "#ffffff", Dark: "#000000"}), // Default background color
-	// )
-	headerStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, true, false)
+	headerStyle lipgloss.Style
 
 	// --- List styles ---
+	listTitleStyle        lipgloss.Style
+	listBlurredTitleStyle lipgloss.Style
+
+	// Style of title and description of selected and not selected journal in list
+	listSelectedTitleStyle lipgloss.Style
+	listSelectedDescStyle  lipgloss.Style
+	listNormalTitleStyle   lipgloss.Style
+	listNormalDescStyle    lipgloss.Style
+
+	// Style for NoItems, status bar and pagination
+	listNoItemsStyle    lipgloss.Style
+	listStatusBarStyle  lipgloss.Style
+	listPaginationStyle lipgloss.Style
+
+	// Style of list footer with loading spinner
+	listFooterStyle  lipgloss.Style
+	listLoadingStyle lipgloss.Style
+
+	// Number of entries marked for bulk actions shown in footer
+	listMarkedStyle lipgloss.Style
+
+	// --- Entry viewer ---
+	viewerPlaceholderStyle lipgloss.Style
+	viewerPromptStyle      lipgloss.Style
+	viewerTextStyle        lipgloss.Style
+	markdownStyle          string
+
+	// Divider between entries list and viewer, it can be dragged with mouse
+	splitDividerStyle lipgloss.Style
+
+	// --- Error log ---
+	errorLogTitleStyle lipgloss.Style
+	errorLogStyle      lipgloss.Style
+
+	// --- Calendar ---
+	calendarStyle         lipgloss.Style
+	calendarWeekdayStyle  lipgloss.Style
+	calendarDayStyle      lipgloss.Style
+	calendarMarkedStyle   lipgloss.Style
+	calendarSelectedStyle lipgloss.Style
+
+	// --- Command palette ---
+	paletteTitleStyle    lipgloss.Style
+	paletteInputStyle    lipgloss.Style
+	paletteItemStyle     lipgloss.Style
+	paletteSelectedStyle lipgloss.Style
+	paletteMatchStyle    lipgloss.Style
+
+	// --- Help overlay ---
+	helpTitleStyle   lipgloss.Style
+	helpOverlayStyle lipgloss.Style
+	helpKeyStyle     lipgloss.Style
+	helpDescStyle    lipgloss.Style
 
-	rightPaddingDatetime = 3 // from different paddings to not cut datetime at right side
+	// --- Footer ---
+	helpStyle  lipgloss.Style
+	debugStyle lipgloss.Style
+
+	// Status line with severity label
+	statusStyle      lipgloss.Style
+	statusInfoStyle  lipgloss.Style
+	statusWarnStyle  lipgloss.Style
+	statusErrorStyle lipgloss.Style
+)
+
+// applyTheme sets styles from theme, it should be called before the model
+// is initialized as components copy styles on creation
+func applyTheme(t theme) {
+	fg := color(t.Foreground)
+	muted := color(t.Muted)
+	accent := color(t.Accent)
+	border := color(t.Border)
 
-	listTitleStyle = lipgloss.NewStyle().UnsetBackground().Bold(true)
+	headerStyle = lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(border).
+		Foreground(fg)
+
+	listTitleStyle = lipgloss.NewStyle().UnsetBackground().Bold(true).Foreground(accent)
+	listBlurredTitleStyle = listTitleStyle.Foreground(muted)
 
-	// Style of title of selected journal in list
 	listSelectedTitleStyle = lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), false, false, false, true).
-				Bold(true).
-				Padding(0, 0, 0, 1) // Padding from left side
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(accent).
+		Foreground(accent).
+		Bold(true).
+		Padding(0, 0, 0, 1) // Padding from left side
 
-	// Style of description of selected journal in list
 	listSelectedDescStyle = lipgloss.NewStyle().
-				Border(lipgloss.NormalBorder(), false, false, false, true).
-				Padding(0, 0, 0, 1) // Padding from left side
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(accent).
+		Foreground(muted).
+		Padding(0, 0, 0, 1) // Padding from left side
+
+	// Selected item is drawn with inverted colors when theme sets its background
+	if t.Selected != "" {
+		listSelectedTitleStyle = invertFBGColors(listSelectedTitleStyle.Background(color(t.Selected)))
+		listSelectedDescStyle = invertFBGColors(listSelectedDescStyle.Foreground(fg).Background(color(t.Selected)))
+	}
 
-	// Style for NoItems
-	listNoItemsStyle = lipgloss.NewStyle()
+	listNormalTitleStyle = lipgloss.NewStyle().Foreground(fg).Padding(0, 0, 0, 2)
+	listNormalDescStyle = lipgloss.NewStyle().Foreground(muted).Padding(0, 0, 0, 2)
 
-	// --- Footer ---
-	helpStyle  = lipgloss.NewStyle().Align(lipgloss.Left).Padding(0, 0, 0, 1).Faint(true)
-	debugStyle = lipgloss.NewStyle().Align(lipgloss.Right).Foreground(lipgloss.Color("#ff0000"))
-)
+	listNoItemsStyle = lipgloss.NewStyle().Foreground(muted)
+	listStatusBarStyle = lipgloss.NewStyle().Foreground(muted).Padding(0, 0, 1, 2)
+	listPaginationStyle = lipgloss.NewStyle().Foreground(muted).PaddingLeft(2)
+
+	listFooterStyle = lipgloss.NewStyle().Padding(0, 0, 0, 2).Foreground(muted)
+	listLoadingStyle = lipgloss.NewStyle().Foreground(accent)
+
+	listMarkedStyle = lipgloss.NewStyle().Foreground(accent).Bold(true).Padding(0, 1, 0, 1)
+
+	viewerPlaceholderStyle = lipgloss.NewStyle().Foreground(muted)
+	viewerPromptStyle = lipgloss.NewStyle().Foreground(accent)
+	viewerTextStyle = lipgloss.NewStyle().Foreground(fg)
+	markdownStyle = t.Markdown
+
+	splitDividerStyle = lipgloss.NewStyle().Foreground(border)
+
+	errorLogTitleStyle = listTitleStyle.Padding(0, 0, 1, 2)
+	errorLogStyle = lipgloss.NewStyle().Padding(0, 0, 0, 2).Foreground(fg)
+
+	calendarStyle = lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	calendarWeekdayStyle = lipgloss.NewStyle().Foreground(muted)
+	calendarDayStyle = lipgloss.NewStyle().Foreground(fg)
+	calendarMarkedStyle = lipgloss.NewStyle().Foreground(accent).Bold(true)
+	calendarSelectedStyle = lipgloss.NewStyle().Foreground(accent).Bold(true).Reverse(true)
+
+	paletteTitleStyle = listTitleStyle.Padding(0, 0, 1, 2)
+	paletteInputStyle = lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	paletteItemStyle = listNormalTitleStyle
+	paletteSelectedStyle = listSelectedTitleStyle
+	paletteMatchStyle = lipgloss.NewStyle().Foreground(accent).Bold(true).Underline(true)
+
+	helpTitleStyle = listTitleStyle.Padding(0, 0, 1, 2)
+	helpOverlayStyle = lipgloss.NewStyle().Padding(0, 0, 0, 2)
+	helpKeyStyle = lipgloss.NewStyle().Foreground(accent)
+	helpDescStyle = lipgloss.NewStyle().Foreground(muted)
+
+	helpStyle = lipgloss.NewStyle().Align(lipgloss.Left).Padding(0, 0, 0, 1)
+	debugStyle = lipgloss.NewStyle().Align(lipgloss.Right).Foreground(color(t.Error))
+
+	statusStyle = lipgloss.NewStyle().Padding(0, 0, 0, 1).Foreground(fg)
+	statusInfoStyle = lipgloss.NewStyle().Bold(true).Foreground(color(t.Info))
+	statusWarnStyle = lipgloss.NewStyle().Bold(true).Foreground(color(t.Warn))
+	statusErrorStyle = lipgloss.NewStyle().Bold(true).Foreground(color(t.Error))
+}
 
 // headerView represents the header view of the TUI
 func (m model) headerView() string {
 	return headerStyle.Width(m.width).Align(lipgloss.Center).Render("Firn")
 }
 
-// footerView represents the footer view of the TUI
+// footerView represents the footer view of the TUI with status line above help
 func (m model) footerView() string {
-	// Show different help based on focus state
-	var helpBindings []key.Binding
-	switch {
-	case m.focusState == focusJournals:
-		helpBindings = []key.Binding{m.keys.quit, m.keys.enter}
-	case m.focusState == focusEntries && m.selectedJournalId != "":
-		helpBindings = []key.Binding{m.keys.esc, m.keys.enter}
-	case m.focusState == focusEntry:
-		helpBindings = []key.Binding{m.keys.esc}
-	default:
-		helpBindings = []key.Binding{m.keys.quit}
+	// Show different help based on focus state, number of marked entries
+	// takes part of help width
+	var marked string
+	if n := len(m.entries.Marked()); n > 0 && m.entriesViewActive() {
+		marked = listMarkedStyle.Render(fmt.Sprintf("%d marked", n))
 	}
-	help := helpStyle.Render(renderHelpBindings(helpBindings))
+	h := m.help
+	h.Width = max(h.Width-lipgloss.Width(marked), 0)
+	help := marked + helpStyle.Render(h.ShortHelpView(m.shortHelp()))
 
 	// Compose debug string
 	var debugStr string
@@ -82,39 +196,15 @@ func (m model) footerView() string {
 	}
 
 	debug := debugStyle.Width(m.width - len(debugStr)).Render(debugStr)
-	return lipgloss.NewStyle().Width(m.width).Render(help + debug)
-}
-
-func renderHelpBindings(bindings []key.Binding) string {
-	if len(bindings) == 0 {
-		return ""
-	}
-
-	parts := make([]string, 0, len(bindings))
-	for _, b := range bindings {
-		if !b.Enabled() {
-			continue
-		}
-
-		helpInfo := b.Help()
-		keyStr := helpInfo.Key
-		desc := helpInfo.Desc
-
-		if keyStr == "" {
-			keyStr = strings.Join(b.Keys(), "/")
-		}
-
-		switch {
-		case keyStr != "" && desc != "":
-			parts = append(parts, fmt.Sprintf("%s %s", keyStr, desc))
-		case keyStr != "":
-			parts = append(parts, keyStr)
-		case desc != "":
-			parts = append(parts, desc)
-		}
+	status := m.status.View(m.width)
+	if m.showPrompt {
+		status = m.prompt.View(m.width)
 	}
 
-	return strings.Join(parts, " | ")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		status,
+		lipgloss.NewStyle().Width(m.width).Render(help+debug),
+	)
 }
 
 // invertFBGColors creates a new style with inverted foreground and background colors
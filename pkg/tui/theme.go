@@ -0,0 +1,167 @@
+//go:build tui
+
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is a color scheme of the TUI. Colors are hex values or ANSI color
+// numbers, empty color keeps terminal default.
+type theme struct {
+	Foreground string `toml:"foreground"` // Main text
+	Muted      string `toml:"muted"`      // Descriptions, hints and placeholders
+	Accent     string `toml:"accent"`     // Titles, selection and spinner
+	Border     string `toml:"border"`     // Header and pane borders
+	Selected   string `toml:"selected"`   // Background of selected item, if set its colors are inverted
+	Info       string `toml:"info"`
+	Warn       string `toml:"warn"`
+	Error      string `toml:"error"`
+	Markdown   string `toml:"markdown"` // Glamour style for entry viewer: dark, light or notty
+}
+
+// Built-in themes, selected by base name in theme config file
+var builtinThemes = map[string]theme{
+	"dark": {
+		Muted:    "245",
+		Accent:   "#87afff",
+		Border:   "240",
+		Info:     "#5fd787",
+		Warn:     "#ffaf00",
+		Error:    "#ff5f5f",
+		Markdown: styles.DarkStyle,
+	},
+	"light": {
+		Muted:    "244",
+		Accent:   "#005fd7",
+		Border:   "250",
+		Info:     "#007a3d",
+		Warn:     "#a66300",
+		Error:    "#c00000",
+		Markdown: styles.LightStyle,
+	},
+	"high-contrast": {
+		Foreground: "#ffffff",
+		Muted:      "#ffffff",
+		Accent:     "#ffff00",
+		Border:     "#ffffff",
+		Selected:   "#000000",
+		Info:       "#00ff00",
+		Warn:       "#ffff00",
+		Error:      "#ff0000",
+		Markdown:   styles.DarkStyle,
+	},
+	// Used when NO_COLOR is set
+	"no-color": {
+		Markdown: styles.NoTTYStyle,
+	},
+}
+
+// Base theme used when theme is not configured, picked by terminal background
+const autoThemeName = "auto"
+
+// themeFile is a structure of theme config file, colors override base theme
+type themeFile struct {
+	Base string `toml:"base"`
+	theme
+}
+
+// themeFilePath returns configured path to theme config file or default
+// location in user config directory
+func themeFilePath(path string) string {
+	if path != "" {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, "firn", "theme.toml")
+}
+
+// loadTheme loads theme from config file on top of its base theme. Base
+// set in configuration takes precedence, NO_COLOR disables all colors.
+func loadTheme(base, file string) (theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return builtinThemes["no-color"], nil
+	}
+
+	var cfg themeFile
+
+	path := themeFilePath(file)
+	if path != "" {
+		meta, err := toml.DecodeFile(path, &cfg)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return theme{}, fmt.Errorf("failed to load theme file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return theme{}, fmt.Errorf("invalid theme file %s: unknown field %q", path, undecoded[0].String())
+		}
+	}
+
+	if base != "" {
+		cfg.Base = base
+	}
+
+	t, err := resolveTheme(cfg)
+	if err != nil {
+		return theme{}, fmt.Errorf("invalid theme file %s: %w", path, err)
+	}
+
+	return t, nil
+}
+
+// resolveTheme applies colors from theme config to its base theme
+func resolveTheme(cfg themeFile) (theme, error) {
+	baseName := cfg.Base
+	if baseName == "" || baseName == autoThemeName {
+		baseName = "light"
+		if lipgloss.HasDarkBackground() {
+			baseName = "dark"
+		}
+	}
+
+	t, ok := builtinThemes[baseName]
+	if !ok {
+		return theme{}, fmt.Errorf("unknown theme %q, must be one of auto, dark, light, high-contrast, no-color", baseName)
+	}
+
+	overrides := []struct {
+		dst *string
+		src string
+	}{
+		{&t.Foreground, cfg.Foreground},
+		{&t.Muted, cfg.Muted},
+		{&t.Accent, cfg.Accent},
+		{&t.Border, cfg.Border},
+		{&t.Selected, cfg.Selected},
+		{&t.Info, cfg.Info},
+		{&t.Warn, cfg.Warn},
+		{&t.Error, cfg.Error},
+		{&t.Markdown, cfg.Markdown},
+	}
+	for _, o := range overrides {
+		if o.src != "" {
+			*o.dst = o.src
+		}
+	}
+
+	return t, nil
+}
+
+// color converts theme color to lipgloss color, empty color means no color
+func color(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
@@ -5,11 +5,16 @@ package tui
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/kompotkot/firn/pkg/db"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -20,6 +25,7 @@ const (
 	focusJournals focusState = "journals"
 	focusEntries  focusState = "entries"
 	focusEntry    focusState = "entry"
+	focusCalendar focusState = "calendar"
 )
 
 type model struct {
@@ -30,7 +36,9 @@ type model struct {
 	width         int
 	contentHeight int
 
-	keys keymap
+	keys     keymap
+	help     help.Model
+	showHelp bool
 
 	selectedJournalId       string
 	selectedJournalName     string
@@ -48,38 +56,115 @@ type model struct {
 	selectedEntryId string
 	viewerFull      bool
 
+	// Share of entries list in split with viewer, in percents
+	splitRatio    int
+	draggingSplit bool
+	headerHeight  int
+
+	// Month calendar filtering entries by creation day
+	calendar     calendarPane
+	showCalendar bool
+
+	// Command palette with actions of current focus state
+	palette     commandPalette
+	showPalette bool
+
+	// Question or confirmation shown in place of status line
+	prompt     promptLine
+	showPrompt bool
+
+	// Sort of journals and entries lists
+	journalsSort listSort
+	entriesSort  listSort
+
+	// Autosave of edited entry and draft left by previous session
+	draftSeq     int    // Editing session, ticks of previous sessions are dropped
+	lastDraft    string // Content written by the last autosave
+	pendingDraft *draft
+
+	// Live refresh polling the database, zero interval disables it
+	refreshInterval time.Duration
+	refreshFailing  bool
+	journalsMarker  db.ChangeMarker // Markers of rows shown by the last refresh
+	entriesMarker   entriesMarker
+
+	// Status line, error log and last failed load to retry
+	status       statusBar
+	errorLog     errorLogPane
+	showErrorLog bool
+	retry        tea.Cmd
+
 	// Debug
 	debugActive bool
 	debugStr    string
 }
 
 // Initialize TUI model
-func initModel(ctx context.Context, database db.Database) model {
+func initModel(ctx context.Context, database db.Database, keys keymap, state tuiState, opts Options) model {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	return model{
+	m := model{
 		ctx:      ctx,
 		database: database,
 
-		keys: initKeymap(),
+		keys: keys,
+		help: initHelp(),
 
 		journals: newJournalsPane(),
 		entries:  newEntriesPane(),
 		viewer:   newEntryViewer(),
+		errorLog: newErrorLogPane(),
+		palette:  newCommandPalette(),
+		prompt:   newPromptLine(),
+		calendar: newCalendarPane(),
+
+		splitRatio:   clampSplitRatio(state.SplitRatio),
+		journalsSort: state.JournalsSort.valid(),
+		entriesSort:  state.EntriesSort.valid(),
 
 		focusState:       focusJournals, // Start with journal list focused
-		debugActive:      initDebug(),
+		debugActive:      opts.Debug,
+		refreshInterval:  max(opts.RefreshInterval, 0),
 		lastJournalIndex: -1,
 	}
+
+	m.journals.SetTitle(m.journalsTitle())
+	m.journals.SetKeyMap(keys)
+	m.entries.SetKeyMap(keys)
+	m.viewer.SetKeyMap(keys)
+	m.errorLog.SetKeyMap(keys)
+
+	return m
 }
 
 // Execute commands concurrently with no ordering guarantees during initialization
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		listJournals(m.ctx, m.database, false, 0, 0),
-	)
+	cmds := []tea.Cmd{
+		listJournals(m.ctx, m.database, m.journalsSort, db.JOURNAL_LIST_DEFAULT_LIMIT, 0),
+		m.journals.spinner.Tick,
+	}
+	if m.refreshInterval > 0 {
+		cmds = append(cmds, refreshTick(m.refreshInterval))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// loadMoreJournals requests next page of journals
+func (m *model) loadMoreJournals() tea.Cmd {
+	offset, limit, tick := m.journals.StartLoading()
+	return tea.Batch(listJournals(m.ctx, m.database, m.journalsSort, limit, offset), tick)
+}
+
+// loadMoreEntries requests next page of selected journal entries
+func (m *model) loadMoreEntries() tea.Cmd {
+	offset, limit, tick := m.entries.StartLoading()
+	if m.showCalendar {
+		return tea.Batch(listDayEntries(m.ctx, m.database, m.selectedJournalId, m.calendar.Day(), m.entriesSort, limit, offset), tick)
+	}
+	return tea.Batch(listEntries(m.ctx, m.database, m.selectedJournalId, m.entriesSort, limit, offset), tick)
 }
 
 // Processes events like window resize, errors, loaded data, and key presses
@@ -90,7 +175,73 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.forceQuit) {
+			return m, tea.Quit
+		}
+
+		// Help overlay is closed by any of its keys
+		if m.showHelp {
+			if key.Matches(msg, m.keys.help, m.keys.esc, m.keys.quit) {
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		// Prompt takes over keyboard until it is answered
+		if m.showPrompt {
+			return m, m.handlePromptKey(msg)
+		}
+
+		// Command palette takes over keyboard until a command is chosen
+		if m.showPalette {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.closePalette()
+			case tea.KeyEnter:
+				command, ok := m.palette.Selected()
+				m.closePalette()
+				if ok {
+					return m, command.run(&m)
+				}
+			default:
+				var cmd tea.Cmd
+				m.palette, cmd = m.palette.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		// Error log takes over keyboard until it is closed
+		if m.showErrorLog {
+			switch {
+			case key.Matches(msg, m.keys.esc, m.keys.errorLog, m.keys.quit):
+				m.showErrorLog = false
+			default:
+				var cmd tea.Cmd
+				m.errorLog, cmd = m.errorLog.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+
+		// Printable keys are typed into textarea while editing
+		if m.viewer.Editing() && msg.Type == tea.KeyRunes {
+			break
+		}
+
 		switch {
+		case key.Matches(msg, m.keys.help) && !m.viewer.Editing():
+			skipListUpdate = true
+			m.showHelp = true
+		case key.Matches(msg, m.keys.palette) && !m.viewer.Editing():
+			skipListUpdate = true
+			m.openPalette()
+		case key.Matches(msg, m.keys.errorLog) && !m.viewer.Editing():
+			skipListUpdate = true
+			m.openErrorLog()
+		case key.Matches(msg, m.keys.retry) && m.retry != nil && !m.viewer.Editing():
+			skipListUpdate = true
+			cmds = append(cmds, m.retryFailed())
 		case key.Matches(msg, m.keys.quit):
 			if m.focusState == focusJournals {
 				return m, tea.Quit
@@ -100,88 +251,151 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case focusEntries:
 				if m.selectedJournalId != "" {
 					skipListUpdate = true
-					m.selectedJournalId = ""
-					m.selectedJournalName = ""
-					m.selectedEntryId = ""
-					m.setFocusState(focusJournals)
-					m.restoreJournalSelection = true
-
-					m.entries.SetTitle("Entries")
-					m.entries.Clear()
-					m.viewer.SetContent("")
-
-					m.resizeComponents()
+					if len(m.entries.Marked()) > 0 {
+						m.entries.ClearMarks()
+						break
+					}
+					if m.showCalendar {
+						m.setFocusState(focusCalendar)
+						break
+					}
+					m.closeJournal()
 				}
+			case focusCalendar:
+				skipListUpdate = true
+				cmds = append(cmds, m.toggleCalendar())
 			case focusEntry:
 				skipListUpdate = true
+
+				// Leave edit mode first, then return to entries. Unsaved
+				// changes are kept as draft to be restored later.
+				if m.viewer.Editing() {
+					m.viewer.StopEditing()
+					if m.viewer.Modified() {
+						cmds = append(cmds,
+							m.autosaveDraft(),
+							m.status.Push(severityWarn, fmt.Sprintf("Entry has unsaved changes, press %s to save them", m.keys.save.Help().Key)),
+						)
+					}
+					break
+				}
 				m.setFocusState(focusEntries)
 				m.resizeComponents()
-
-				// TODO(kompotkot) Enter will trigger save logic later
+			}
+		case key.Matches(msg, m.keys.edit) && m.focusState == focusEntry && !m.viewer.Editing():
+			skipListUpdate = true
+			cmds = append(cmds, m.startEditing())
+		case key.Matches(msg, m.keys.save) && m.focusState == focusEntry && m.viewer.Modified():
+			skipListUpdate = true
+			cmds = append(cmds, m.saveEdited())
+		case key.Matches(msg, m.keys.restore) && m.hasDraft():
+			skipListUpdate = true
+			cmds = append(cmds, m.restoreDraft())
+		case key.Matches(msg, m.keys.editor) && m.editorAvailable():
+			return m, m.openSelectedInEditor()
+		case key.Matches(msg, m.keys.nextPane, m.keys.prevPane) && m.wideLayout() && !m.viewer.Editing():
+			skipListUpdate = true
+			step := 1
+			if key.Matches(msg, m.keys.prevPane) {
+				step = -1
+			}
+			m.cycleFocus(step)
+		case key.Matches(msg, m.keys.growList, m.keys.shrinkList) && m.focusState == focusEntries && !m.wideLayout():
+			skipListUpdate = true
+			step := splitRatioStep
+			if key.Matches(msg, m.keys.shrinkList) {
+				step = -step
+			}
+			m.setSplitRatio(m.splitRatio + step)
+		case key.Matches(msg, m.keys.calendar) && m.entriesShown() && m.focusState != focusJournals && !m.viewer.Editing():
+			skipListUpdate = true
+			cmds = append(cmds, m.toggleCalendar())
+		case key.Matches(msg, m.keys.sortOrder, m.keys.sortField) && (m.focusState == focusJournals || m.focusState == focusEntries):
+			skipListUpdate = true
+			cmds = append(cmds, m.sortFocusedList(key.Matches(msg, m.keys.sortOrder)))
+		case key.Matches(msg, m.keys.yank) && m.entrySelected() && !m.viewer.Editing():
+			skipListUpdate = true
+			cmds = append(cmds, m.yankSelected())
+		case key.Matches(msg, m.keys.yankId) && !m.viewer.Editing():
+			skipListUpdate = true
+			cmds = append(cmds, m.yankSelectedId())
+		case key.Matches(msg, m.keys.mark) && m.focusState == focusEntries && m.entriesViewActive():
+			skipListUpdate = true
+			m.toggleMark()
+			cmds = append(cmds, m.syncSelectedEntry(m.selectedEntryId))
+		case key.Matches(msg, m.keys.delete, m.keys.tag, m.keys.untag, m.keys.move) && m.focusState == focusEntries && m.entriesViewActive():
+			skipListUpdate = true
+			switch {
+			case key.Matches(msg, m.keys.delete):
+				cmds = append(cmds, m.deleteMarked())
+			case key.Matches(msg, m.keys.tag):
+				cmds = append(cmds, m.tagMarked())
+			case key.Matches(msg, m.keys.untag):
+				cmds = append(cmds, m.untagMarked())
+			default:
+				cmds = append(cmds, m.moveMarked())
 			}
 		case key.Matches(msg, m.keys.enter):
 			switch m.focusState {
+			case focusCalendar:
+				m.setFocusState(focusEntries)
+				return m, nil
 			case focusJournals:
-				if selectedJournal, ok := m.journals.SelectedJournal(); ok {
-					m.selectedJournalId = selectedJournal.Id
-					m.selectedJournalName = selectedJournal.Name
-					m.lastJournalIndex = m.journals.GlobalIndex()
-					m.selectedEntryId = ""
-					m.setFocusState(focusEntries)
-
-					m.entries.Clear()
-					m.entries.SetTitle(fmt.Sprintf("%s entries", m.selectedJournalName))
-					m.viewer.SetContent("")
-					m.resizeComponents()
-
-					return m, tea.Batch(
-						listEntries(m.ctx, m.database, m.selectedJournalId, false, 0, 0),
-					)
+				if cmd, ok := m.openSelectedJournal(); ok {
+					return m, cmd
 				}
 			case focusEntries:
-				if selectedEntry, ok := m.entries.SelectedEntry(); ok {
-					m.selectedEntryId = selectedEntry.Id
-					m.setFocusState(focusEntry)
-					m.resizeComponents()
-
-					return m, getEntryById(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId)
+				if cmd, ok := m.openSelectedEntry(); ok {
+					return m, cmd
 				}
 			}
 		}
 
-	// Journals loaded from database
+	case tea.MouseMsg:
+		skipListUpdate = true
+		cmds = append(cmds, m.handleMouse(msg))
+
+	// Journals page loaded from database
 	case journalsLoadedMsg:
+		if !m.journals.AcceptsPage(msg.offset) {
+			break
+		}
+		m.journals.PageLoaded(msg.offset, len(msg.journals))
+
 		if len(msg.journals) > 0 {
 			items := make([]list.Item, len(msg.journals))
 			for i, j := range msg.journals {
-				items[i] = jItem{
-					journal:    j,
-					widthTitle: m.width - len(j.Name) - magicWidthPaddingNum,
-					widthDesc:  m.width - len(fmt.Sprintf("ID: %s", j.Id)) - magicWidthPaddingNum, // Available width for Description (full width minus ID length)
-				}
+				items[i] = newJItem(j, m.width)
+			}
+			if msg.offset == 0 {
+				m.journals.SetItems(items)
+			} else {
+				m.journals.AppendItems(items)
 			}
-			m.journals.SetItems(items)
-			m.journals.SetTotalPages(len(msg.journals))
 		}
 		m.resizeComponents()
 
+	// Entries page loaded from database
 	case entriesLoadedMsg:
-		if msg.journalId != m.selectedJournalId {
+		if msg.journalId != m.selectedJournalId || !msg.day.Equal(m.entriesDay()) || !m.entries.AcceptsPage(msg.offset) {
 			break
 		}
+		m.entries.PageLoaded(msg.offset, len(msg.entries))
 
-		// Always update the entry list, even if empty (to clear old entries)
 		items := make([]list.Item, len(msg.entries))
 		for i, e := range msg.entries {
-			idLabel := fmt.Sprintf("ID: %s", e.Id)
-			items[i] = eItem{
-				entry:      e,
-				widthTitle: m.width - len(e.Title) - magicWidthPaddingNum,
-				widthDesc:  m.width - len(idLabel) - magicWidthPaddingNum,
-			}
+			items[i] = newEItem(e, m.width)
 		}
+
+		// Next pages are appended, selection is kept as is
+		if msg.offset > 0 {
+			m.entries.AppendItems(items)
+			m.resizeComponents()
+			break
+		}
+
+		// Always update the entry list, even if empty (to clear old entries)
 		m.entries.SetItems(items)
-		m.entries.SetTotalPages(len(msg.entries))
 
 		// Auto-select first entry and load its content if entries exist
 		if len(msg.entries) > 0 && m.entries.SelectedItem() != nil {
@@ -204,9 +418,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Entry loaded by ID - update textarea with content
+		m.pendingDraft = nil
 		if msg.entry != nil {
 			// Set content and ensure textarea is updated
 			m.viewer.SetContent(msg.entry.Content)
+			cmds = append(cmds, findDraft(msg.journalId, msg.entryId))
 		} else {
 			// Entry not found - clear textarea
 			m.viewer.SetContent("Entry not found")
@@ -216,20 +432,136 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.resizeComponents()
 
+	// Entry saved from editor for the first time is shown in list
+	case entryCreatedMsg:
+		if msg.entry == nil || msg.entry.JournalId != m.selectedJournalId {
+			break
+		}
+
+		// Entry is listed only if it belongs to the day shown in calendar
+		day := m.entriesDay()
+		_, entriesWidth, _ := m.columnWidths()
+		if (day.IsZero() || day.Equal(startOfDay(msg.entry.CreatedAt))) && m.entriesSort.OrderBy != db.OrderByName {
+			if m.entries.AddEntry(newEItem(*msg.entry, entriesWidth), m.entriesSort.Desc) {
+				m.selectedEntryId = msg.entry.Id
+				m.viewer.SetContent(msg.entry.Content)
+			}
+		}
+		m.resizeComponents()
+
+		if m.showCalendar {
+			cmds = append(cmds, listEntryDates(m.ctx, m.database, m.selectedJournalId, m.calendar.Month()))
+		}
+		return m, tea.Batch(cmds...)
+
+	case entryDatesLoadedMsg:
+		if msg.journalId == m.selectedJournalId && msg.month.Equal(m.calendar.Month()) {
+			m.calendar.SetDates(msg.month, msg.dates)
+		}
+
+	case entryExportedMsg:
+		cmds = append(cmds, m.status.Push(severityInfo, fmt.Sprintf("Entry exported to %s", msg.path)))
+
+	case entriesBulkDoneMsg:
+		cmds = append(cmds, m.applyBulkDone(msg))
+
+	case clipboardCopiedMsg:
+		text := fmt.Sprintf("Copied %s to clipboard", msg.what)
+		if msg.osc52 {
+			text += " via terminal"
+		}
+		cmds = append(cmds, m.status.Push(severityInfo, text))
+
+	// Entry file is written, suspend the program and open it in editor
+	case editorReadyMsg:
+		return m, openEditor(msg)
+
+	case editorFinishedMsg:
+		return m, saveEntryFile(m.ctx, m.database, msg)
+
+	case entrySavedMsg:
+		if msg.entry == nil {
+			break
+		}
+
+		// Saved content replaces draft of previous autosaves
+		cmds = append(cmds, discardDraft(msg.entry.Id))
+		if msg.entry.JournalId != m.selectedJournalId {
+			break
+		}
+
+		m.entries.UpdateEntry(*msg.entry)
+		_, entriesWidth, _ := m.columnWidths()
+		m.entries.UpdateWidths(entriesWidth)
+		if msg.entry.Id == m.selectedEntryId {
+			m.lastDraft = ""
+			if m.viewer.Editing() {
+				m.viewer.SetSaved(msg.entry.Content)
+			} else {
+				m.viewer.SetContent(msg.entry.Content)
+			}
+		}
+		cmds = append(cmds, m.status.Push(severityInfo, fmt.Sprintf("Entry %q saved", msg.entry.Title)))
+
+	case statusExpiredMsg:
+		m.status.Expire(msg.id)
+
+	// Poll the database for changes made by other clients
+	case refreshTickMsg:
+		cmds = append(cmds, m.refresh(), refreshTick(m.refreshInterval))
+
+	case journalsRefreshedMsg:
+		m.refreshFailing = false
+		m.applyJournalsRefresh(msg)
+
+	case entriesRefreshedMsg:
+		m.refreshFailing = false
+		cmds = append(cmds, m.applyEntriesRefresh(msg))
+
+	case refreshFailedMsg:
+		if !m.refreshFailing {
+			m.refreshFailing = true
+			cmds = append(cmds, m.status.Push(severityWarn, fmt.Sprintf("%s: %v", msg.operation, msg.err)))
+		}
+
+	// Autosave edited content while editing session lasts
+	case draftTickMsg:
+		if msg.seq != m.draftSeq || !m.viewer.Editing() {
+			break
+		}
+		cmds = append(cmds, m.autosaveDraft(), draftTick(msg.seq))
+
+	// Draft of previous session is offered to be restored, it is dropped
+	// if it does not differ from saved content
+	case draftFoundMsg:
+		if msg.draft.EntryId != m.selectedEntryId || m.viewer.Editing() {
+			break
+		}
+		if msg.draft.Content == m.viewer.Value() {
+			cmds = append(cmds, discardDraft(msg.draft.EntryId))
+			break
+		}
+		m.pendingDraft = &msg.draft
+		cmds = append(cmds, m.status.Push(severityWarn, fmt.Sprintf(
+			"Entry has unsaved draft from %s, press %s to restore it",
+			msg.draft.SavedAt.Local().Format(datetimeFormat), m.keys.restore.Help().Key,
+		)))
+
 	// Window size changed
 	case tea.WindowSizeMsg:
 		// Update width for dynamic item rendering first (needed for header/footer calculation)
 		m.width = msg.Width
+		m.help.Width = msg.Width
 
 		header := m.headerView()
 		footer := m.footerView()
 
 		// Calculate actual heights including any styling (borders, padding, etc.)
-		headerHeight := lipgloss.Height(header)
+		m.headerHeight = lipgloss.Height(header)
 		footerHeight := lipgloss.Height(footer)
 
 		// Calculate available height for lists
-		availableHeight := msg.Height - headerHeight - footerHeight
+		availableHeight := msg.Height - m.headerHeight - footerHeight
 		if availableHeight < 0 {
 			availableHeight = 0
 		}
@@ -239,9 +571,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.resizeComponents()
 
+	// Advance spinners of panes waiting for next page
+	case spinner.TickMsg:
+		skipListUpdate = true
+
+		var journalsCmd, entriesCmd tea.Cmd
+		m.journals, journalsCmd = m.journals.UpdateSpinner(msg)
+		m.entries, entriesCmd = m.entries.UpdateSpinner(msg)
+		cmds = append(cmds, journalsCmd, entriesCmd)
+
 	case errMsg:
-		// Handle errors - could display in a status bar or log
 		m.debugStr = fmt.Sprintf("%s: %v", msg.operation, msg.err)
+
+		// Help in footer offers retry while it is available
+		cmds = append(cmds, m.status.Push(severityError, fmt.Sprintf("%s: %v", msg.operation, msg.err)))
+		m.retry = msg.retry
+
+		// Failed page will be requested again when cursor moves
+		m.journals.StopLoading()
+		m.entries.StopLoading()
 	}
 
 	// Handle keyboard events for lists and textarea
@@ -253,6 +601,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.focusState == focusJournals {
 			m.journals, listCmd = m.journals.Update(msg)
 			cmds = append(cmds, listCmd)
+
+			// Load next page when cursor approaches the end of loaded journals
+			if m.journals.NeedsMore() {
+				cmds = append(cmds, m.loadMoreJournals())
+			}
 		} else if m.focusState == focusEntries && m.selectedJournalId != "" {
 			// Store previous selected entry ID to detect changes
 			previousEntryId := m.selectedEntryId
@@ -260,21 +613,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.entries, listCmd = m.entries.Update(msg)
 			cmds = append(cmds, listCmd)
 
-			// Check if selection changed and load entry content
-			if entry, ok := m.entries.SelectedEntry(); ok {
-				if entry.Id != previousEntryId {
-					m.selectedEntryId = entry.Id
-					cmds = append(cmds, getEntryById(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId))
-				}
-			} else {
-				// No item selected, clear entry
-				m.selectedEntryId = ""
-				m.viewer.SetContent("")
+			// Load next page when cursor approaches the end of loaded entries
+			if m.entries.NeedsMore() {
+				cmds = append(cmds, m.loadMoreEntries())
 			}
 
+			// Check if selection changed and load entry content
+			cmds = append(cmds, m.syncSelectedEntry(previousEntryId))
+
 			// Update textarea while entry list is visible (even if blurred)
 			m.viewer, viewerCmd = m.viewer.Update(msg)
 			cmds = append(cmds, viewerCmd)
+		} else if m.focusState == focusCalendar {
+			// Arrow keys select day and list its entries
+			if keyMsg, ok := msg.(tea.KeyMsg); ok {
+				if day, moved := m.calendar.Move(keyMsg, m.keys); moved {
+					cmds = append(cmds, m.selectDay(day))
+				}
+			}
 		} else if m.focusState == focusEntry && m.selectedJournalId != "" {
 			// Only textarea should react to input in entry focus
 			m.viewer, viewerCmd = m.viewer.Update(msg)
@@ -299,6 +655,136 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// openSelectedJournal shows entries of the journal selected in list
+func (m *model) openSelectedJournal() (tea.Cmd, bool) {
+	selectedJournal, ok := m.journals.SelectedJournal()
+	if !ok {
+		return nil, false
+	}
+
+	m.selectedJournalId = selectedJournal.Id
+	m.selectedJournalName = selectedJournal.Name
+	m.lastJournalIndex = m.journals.GlobalIndex()
+	m.selectedEntryId = ""
+	m.setFocusState(focusEntries)
+
+	m.entries.Clear()
+	m.entries.SetTitle(m.entriesTitle())
+	m.viewer.SetContent("")
+	m.resizeComponents()
+
+	if m.showCalendar {
+		m.calendar.Reset()
+		return tea.Batch(m.loadMoreEntries(), listEntryDates(m.ctx, m.database, m.selectedJournalId, m.calendar.Month())), true
+	}
+	return m.loadMoreEntries(), true
+}
+
+// openPalette shows command palette with actions of current focus state
+func (m *model) openPalette() {
+	m.showPalette = true
+	m.palette.Open("Command palette", m.paletteCommands())
+}
+
+func (m *model) closePalette() {
+	m.showPalette = false
+	m.palette.Close()
+}
+
+func (m *model) openErrorLog() {
+	m.showErrorLog = true
+	m.errorLog.SetContent(m.status.LogView())
+}
+
+// retryFailed repeats the last failed load
+func (m *model) retryFailed() tea.Cmd {
+	if m.retry == nil {
+		return nil
+	}
+	cmd := tea.Batch(m.retry, m.status.Push(severityInfo, "Retrying..."))
+	m.retry = nil
+	return cmd
+}
+
+// openSelectedInEditor opens selected entry in external editor
+func (m *model) openSelectedInEditor() tea.Cmd {
+	if !m.entrySelected() {
+		return nil
+	}
+	return prepareEntryFile(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId)
+}
+
+// newEntry opens a new entry of selected journal in external editor, it is
+// created when the editor is closed with changes saved
+func (m *model) newEntry() tea.Cmd {
+	if !m.entriesViewActive() {
+		return nil
+	}
+	return prepareNewEntryFile(m.selectedJournalId)
+}
+
+// exportSelectedEntry writes selected entry to Markdown file in working directory
+func (m *model) exportSelectedEntry() tea.Cmd {
+	if !m.entrySelected() {
+		return nil
+	}
+	return exportEntry(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId, ".")
+}
+
+// closeJournal returns from entries of selected journal to journals list
+func (m *model) closeJournal() {
+	m.selectedJournalId = ""
+	m.selectedJournalName = ""
+	m.selectedEntryId = ""
+	m.setFocusState(focusJournals)
+	m.restoreJournalSelection = true
+
+	m.entries.SetTitle("Entries")
+	m.entries.Clear()
+	m.viewer.SetContent("")
+
+	m.resizeComponents()
+}
+
+// openSelectedEntry focuses viewer with the entry selected in list
+func (m *model) openSelectedEntry() (tea.Cmd, bool) {
+	selectedEntry, ok := m.entries.SelectedEntry()
+	if !ok {
+		return nil, false
+	}
+
+	m.selectedEntryId = selectedEntry.Id
+	m.setFocusState(focusEntry)
+	m.resizeComponents()
+
+	return getEntryById(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId), true
+}
+
+// syncSelectedEntry loads content of entry selected in list if selection
+// changed from previousEntryId
+func (m *model) syncSelectedEntry(previousEntryId string) tea.Cmd {
+	entry, ok := m.entries.SelectedEntry()
+	if !ok {
+		// No item selected, clear entry
+		m.selectedEntryId = ""
+		m.viewer.SetContent("")
+		return nil
+	}
+
+	if entry.Id == previousEntryId {
+		return nil
+	}
+
+	m.selectedEntryId = entry.Id
+	return getEntryById(m.ctx, m.database, m.selectedJournalId, m.selectedEntryId)
+}
+
+// setSplitRatio changes share of entries list in split with viewer
+func (m *model) setSplitRatio(ratio int) {
+	m.splitRatio = clampSplitRatio(ratio)
+	m.resizeComponents()
+}
+
 func (m model) entriesViewActive() bool {
 	return m.selectedJournalId != ""
 }
@@ -307,6 +793,17 @@ func (m model) entrySelected() bool {
 	return m.selectedEntryId != ""
 }
 
+// editorAvailable reports whether selected entry could be opened in external editor
+func (m model) editorAvailable() bool {
+	switch m.focusState {
+	case focusEntries:
+		return m.entrySelected()
+	case focusEntry:
+		return m.entrySelected() && !m.viewer.Editing()
+	}
+	return false
+}
+
 func (m *model) ensureTextareaFocus(active bool) {
 	if active {
 		if !m.viewer.Focused() {
@@ -334,13 +831,47 @@ func (m *model) setFocusState(next focusState) {
 		} else {
 			m.ensureTextareaFocus(true)
 		}
+	case focusCalendar:
+		if !m.entriesViewActive() {
+			next = focusJournals
+		} else if !m.showCalendar {
+			next = focusEntries
+		}
+		m.ensureTextareaFocus(false)
 	default:
 		next = focusJournals
 		m.ensureTextareaFocus(false)
 	}
 
-	m.viewerFull = next == focusEntry
 	m.focusState = next
+	m.viewerFull = next == focusEntry && !m.wideLayout()
+
+	m.journals.SetFocused(next == focusJournals)
+	m.entries.SetFocused(next == focusEntries)
+	m.calendar.SetFocused(next == focusCalendar)
+}
+
+// splitHeights returns heights of entries list and viewer in split view,
+// one line between them is taken by divider
+func (m model) splitHeights() (int, int) {
+	listHeight := (m.contentHeight * m.splitRatio) / 100
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	textHeight := m.contentHeight - listHeight - 1
+	if textHeight < 1 {
+		textHeight = 1
+	}
+	return listHeight, textHeight
+}
+
+// entriesListHeight returns height of entries list in region of given
+// height, calendar takes its top when shown
+func (m model) entriesListHeight(height int) int {
+	if !m.showCalendar {
+		return height
+	}
+	return max(height-calendarHeight, 1)
 }
 
 func (m *model) resizeComponents() {
@@ -348,7 +879,23 @@ func (m *model) resizeComponents() {
 		return
 	}
 
-	if !m.entriesViewActive() {
+	m.errorLog.SetSize(m.width, m.contentHeight)
+	m.palette.SetSize(m.width, m.contentHeight)
+
+	// Viewer takes the whole screen in stacked layout only
+	m.viewerFull = m.focusState == focusEntry && !m.wideLayout()
+
+	if m.wideLayout() {
+		journalsWidth, entriesWidth, viewerWidth := m.columnWidths()
+		m.journals.UpdateWidths(journalsWidth)
+		m.journals.SetSize(journalsWidth, m.contentHeight)
+		m.entries.UpdateWidths(entriesWidth)
+		m.entries.SetSize(entriesWidth, m.entriesListHeight(m.contentHeight))
+		m.viewer.SetSize(viewerWidth, m.contentHeight)
+		return
+	}
+
+	if !m.entriesShown() {
 		m.journals.UpdateWidths(m.width)
 		m.journals.SetSize(m.width, m.contentHeight)
 		return
@@ -361,28 +908,38 @@ func (m *model) resizeComponents() {
 		return
 	}
 
-	listHeight := (m.contentHeight * 60) / 100
-	if listHeight < 1 {
-		listHeight = 1
-	}
-	textHeight := m.contentHeight - listHeight
-	if textHeight < 1 {
-		textHeight = 1
-	}
+	listHeight, textHeight := m.splitHeights()
 
-	m.entries.SetSize(m.width, listHeight)
+	m.entries.SetSize(m.width, m.entriesListHeight(listHeight))
 	m.viewer.SetSize(m.width, textHeight)
 }
 
 // contentView returns the combined view of both lists (without header/footer)
 func (m model) contentView() string {
+	if m.showHelp {
+		return m.helpOverlayView()
+	}
+
+	if m.showErrorLog {
+		return m.errorLog.View()
+	}
+
+	if m.showPalette {
+		return m.palette.View()
+	}
+
+	if m.wideLayout() {
+		return m.wideContentView()
+	}
+
 	// If a journal is selected, show entry list/textarea combo
-	if m.entriesViewActive() {
+	if m.entriesShown() {
 		if m.viewerFull {
 			return m.viewer.View()
 		}
 		return lipgloss.JoinVertical(lipgloss.Left,
-			m.entries.View(),
+			m.entriesColumnView(),
+			splitDividerStyle.Render(strings.Repeat("─", m.width)),
 			m.viewer.View(),
 		)
 	}
@@ -408,8 +965,47 @@ func (m model) View() string {
 	)
 }
 
-func ShowTui(ctx context.Context, database db.Database) error {
-	p := tea.NewProgram(initModel(ctx, database), tea.WithAltScreen())
-	_, err := p.Run()
-	return err
+func ShowTui(ctx context.Context, database db.Database, opts Options) error {
+	keys, err := loadKeymap(opts.KeymapFile)
+	if err != nil {
+		return err
+	}
+
+	t, err := loadTheme(opts.Theme, opts.ThemeFile)
+	if err != nil {
+		return err
+	}
+	applyTheme(t)
+
+	p := tea.NewProgram(
+		initModel(ctx, database, keys, loadState(), opts),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+		tea.WithOutput(output),
+	)
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	// Remember layout for the next session and keep unsaved changes
+	if m, ok := final.(model); ok {
+		if m.entrySelected() && m.viewer.Modified() {
+			d := draft{EntryId: m.selectedEntryId, JournalId: m.selectedJournalId, Content: m.viewer.Value(), SavedAt: time.Now()}
+			if err := storeDraft(d); err != nil {
+				return err
+			}
+		}
+		// Session itself succeeded, so losing layout only deserves a warning
+		err := saveState(tuiState{
+			SplitRatio:   m.splitRatio,
+			JournalsSort: m.journalsSort,
+			EntriesSort:  m.entriesSort,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save TUI state: %v\n", err)
+		}
+	}
+
+	return nil
 }